@@ -0,0 +1,146 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package datetime
+
+import (
+	"context"
+	"sync"
+	"time"
+	"unicode"
+)
+
+/*
+ * This file contains the cache of the results from a datetime service
+ */
+
+//DefaultCacheSize is the default no. of results stored in the cache
+const DefaultCacheSize = 1000
+
+//cacheKey is the key with which the results are cached
+type cacheKey struct {
+	//sentence is the normalised sentence
+	sentence string
+	//ref is the reference time against which the sentence was resolved
+	ref int64
+	//locale and timezone of the query
+	locale   string
+	timezone string
+}
+
+//Cache is a datetime service which caches the results of the service it wraps.
+//Results are cached with the lower cased sentence, the reference time truncated to the minute, the locale and the timezone.
+//When the cache is full, the oldest results are evicted first
+type Cache struct {
+	//Service is the service whose results are cached
+	Service Service
+	//Size is the maximum no. of results stored in the cache
+	Size int
+	//Now returns the reference time for the queries without one in their options
+	Now func() time.Time
+
+	entries map[cacheKey]Results
+	keys    []cacheKey
+	lock    sync.Mutex
+}
+
+//NewCache returns a cache wrapping the given service
+func NewCache(s Service, size int) *Cache {
+	return &Cache{Service: s, Size: size, Now: time.Now, entries: map[cacheKey]Results{}}
+}
+
+//Query returns a channel of Response. Which will return the reponse in an concurrent environment
+func (c *Cache) Query(ctx context.Context, query []rune, opts Options) chan Results {
+	/*
+	 * If the results exist in the cache we will return them with the body taken from the query
+	 * Else we will query the service and cache the results if there are no errors
+	 */
+	ch := make(chan Results, 1)
+	if opts.RefTime.IsZero() {
+		opts.RefTime = time.Now()
+		if c.Now != nil {
+			opts.RefTime = c.Now()
+		}
+	}
+	key := cacheKey{
+		sentence: normalise(query),
+		ref:      opts.RefTime.Truncate(time.Minute).Unix(),
+		locale:   opts.Locale,
+		timezone: opts.Timezone,
+	}
+
+	c.lock.Lock()
+	res, ok := c.entries[key]
+	c.lock.Unlock()
+	if ok {
+		ch <- withBody(res, query)
+		return ch
+	}
+
+	go func() {
+		res := <-c.Service.Query(ctx, query, opts)
+		if res.Err == nil {
+			c.add(key, withBody(res, query))
+		}
+		ch <- res
+	}()
+	return ch
+}
+
+//add adds the results to the cache evicting the oldest results if the cache is full
+func (c *Cache) add(key cacheKey, res Results) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.Size <= 0 {
+		return
+	}
+	if c.entries == nil {
+		c.entries = map[cacheKey]Results{}
+	}
+	if _, ok := c.entries[key]; !ok {
+		c.keys = append(c.keys, key)
+	}
+	c.entries[key] = res
+	for len(c.keys) > c.Size {
+		delete(c.entries, c.keys[0])
+		c.keys = c.keys[1:]
+	}
+}
+
+//Len returns the no. of results in the cache
+func (c *Cache) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.entries)
+}
+
+//normalise returns the lower cased sentence. The rune offsets of the sentence are preserved
+func normalise(query []rune) string {
+	lowered := make([]rune, len(query))
+	for i, r := range query {
+		lowered[i] = unicode.ToLower(r)
+	}
+	return string(lowered)
+}
+
+//withBody returns a copy of the results with the body of the responses taken from the given query
+func withBody(res Results, query []rune) Results {
+	result := Results{Res: make([]Response, len(res.Res))}
+	for i, r := range res.Res {
+		//the intervals are copied as the validation of the value updates them
+		if r.Value.From != nil {
+			from := *r.Value.From
+			r.Value.From = &from
+		}
+		if r.Value.To != nil {
+			to := *r.Value.To
+			r.Value.To = &to
+		}
+		if r.Start >= 0 && r.Start <= r.End && r.End <= len(query) {
+			r.Body = string(query[r.Start:r.End])
+		}
+		result.Res[i] = r
+	}
+	return result
+}
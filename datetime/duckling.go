@@ -5,12 +5,14 @@
 package datetime
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"log"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -38,13 +40,14 @@ func NewDuckling() (*Duckling, error) {
 }
 
 //Query returns a channel of Response. Which will return the reponse in an concurrent environment
-func (d *Duckling) Query(query []rune) chan Results {
-	ch := make(chan Results)
-	go d.hitAPI(ch, string(query))
+//The request to the api is cancelled once the context is done
+func (d *Duckling) Query(ctx context.Context, query []rune, opts Options) chan Results {
+	ch := make(chan Results, 1)
+	go d.hitAPI(ctx, ch, string(query), opts)
 	return ch
 }
 
-func (d *Duckling) hitAPI(resp chan Results, query string) {
+func (d *Duckling) hitAPI(ctx context.Context, resp chan Results, query string, opts Options) {
 	/*
 	 * We will hit the api
 	 * Then we will decode the response
@@ -54,10 +57,20 @@ func (d *Duckling) hitAPI(resp chan Results, query string) {
 	formData := url.Values{
 		"text": {query},
 	}
+	if !opts.RefTime.IsZero() {
+		formData.Set("reftime", strconv.FormatInt(opts.RefTime.UnixNano()/int64(time.Millisecond), 10))
+	}
+	if len(opts.Timezone) != 0 {
+		formData.Set("tz", opts.Timezone)
+	}
+	if len(opts.Locale) != 0 {
+		formData.Set("locale", opts.Locale)
+	}
 	client := http.Client{Timeout: time.Second * 10}
-	req, err := http.NewRequest("POST", d.url+d.parseAPI, strings.NewReader(formData.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", d.url+d.parseAPI, strings.NewReader(formData.Encode()))
 	if err != nil {
 		log.Println("Error while creating the request for duckling api", d.url+d.parseAPI, err)
+		result.Err = err
 		resp <- result
 		return
 	}
@@ -65,9 +78,11 @@ func (d *Duckling) hitAPI(resp chan Results, query string) {
 	res, err := client.Do(req)
 	if err != nil {
 		log.Println("Error while htting the duckling api", d.url+d.parseAPI, err)
+		result.Err = err
 		resp <- result
 		return
 	}
+	defer res.Body.Close()
 
 	//parsing the response
 	dec := json.NewDecoder(res.Body)
@@ -75,6 +90,7 @@ func (d *Duckling) hitAPI(resp chan Results, query string) {
 	er := dec.Decode(&dm)
 	if er != nil {
 		log.Println("Error while parsing the response from the duckling api", d.url+d.parseAPI, er)
+		result.Err = er
 		resp <- result
 		return
 	}
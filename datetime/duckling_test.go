@@ -4,6 +4,12 @@
 
 package datetime
 
+import "context"
+
+import "net/http"
+
+import "net/http/httptest"
+
 import "testing"
 
 import "time"
@@ -14,7 +20,7 @@ func TestDuckling(t *testing.T) {
 		t.Error("Error while initalizing the duckling service", err)
 		return
 	}
-	ch := d.Query([]rune("this month"))
+	ch := d.Query(context.Background(), []rune("this month"), Options{})
 	res := <-ch
 	if len(res.Res) == 0 {
 		t.Error("Expected today to resolved. Got empty result")
@@ -31,3 +37,27 @@ func TestDuckling(t *testing.T) {
 		return
 	}
 }
+
+func TestDucklingCancel(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	d := &Duckling{url: server.URL, parseAPI: "/parse"}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	select {
+	case res := <-d.Query(ctx, []rune("this month"), Options{}):
+		if res.Err == nil {
+			t.Error("Expected an error once the context is done. Got", res.Res)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Expected the request to duckling to be cancelled with the context")
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package datetime
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+/*
+ * This file contains the utilities for interpreting the fiscal periods like this financial year, FY19 Q2
+ */
+
+//FiscalType is the type of the value denoting a fiscal period.
+//Fiscal periods can be resolved to a date range only with the fiscal calendar of the data
+const FiscalType = "fiscal"
+
+//FiscalPeriod is a period in the fiscal calendar. A fiscal year is named by the calendar year in which it ends.
+//So with fiscal years starting in April, FY19 is from April 2018 to March 2019
+type FiscalPeriod struct {
+	//Year is the calendar year in which the fiscal year ends. It is zero for the periods relative to the reference time
+	Year int `json:"year,omitempty"`
+	//Quarter is the fiscal quarter from 1 to 4. It is zero if the period is the whole fiscal year
+	Quarter int `json:"quarter,omitempty"`
+	//Gran is the grain of the period. It can be year or quarter
+	Gran string `json:"gran,omitempty"`
+	//Offset is the no. of periods from the period of the reference time for the relative periods. Like -1 for last financial year
+	Offset int `json:"offset,omitempty"`
+	//RefTime is the reference time against which the relative periods are resolved
+	RefTime time.Time `json:"ref_time,omitempty"`
+}
+
+//Range returns the half open range [from, to) of the period in the fiscal calendar starting at the given month.
+//If the start month is not valid, the fiscal year is considered to be the calendar year
+func (f FiscalPeriod) Range(start time.Month) (from, to time.Time) {
+	/*
+	 * We will find the end year of the fiscal year
+	 * Then we will find the start of the period in the fiscal year
+	 * Relative periods are then moved by the offset
+	 */
+	if start < time.January || start > time.December {
+		start = time.January
+	}
+	loc := f.RefTime.Location()
+
+	//fiscal year and the months since its start
+	year, months := f.Year, 0
+	if year == 0 {
+		year = f.RefTime.Year()
+		if start != time.January && f.RefTime.Month() >= start {
+			year++
+		}
+		months = (int(f.RefTime.Month()) - int(start) + 12) % 12
+	}
+	startYear := year
+	if start != time.January {
+		startYear--
+	}
+	fyStart := time.Date(startYear, start, 1, 0, 0, 0, 0, loc)
+
+	//quarters
+	if f.Gran == GrainQuarter {
+		quarter := f.Quarter - 1
+		if f.Year == 0 {
+			quarter = months / 3
+		}
+		from = fyStart.AddDate(0, 3*(quarter+f.Offset), 0)
+		return from, from.AddDate(0, 3, 0)
+	}
+
+	//years
+	from = fyStart
+	if f.Year == 0 {
+		from = from.AddDate(f.Offset, 0, 0)
+	}
+	return from, from.AddDate(1, 0, 0)
+}
+
+//ResolveFiscal returns the value of the fiscal period as an interval in the fiscal calendar starting at the given month.
+//If the value is not a fiscal period, it is returned as it is
+func (v Value) ResolveFiscal(start time.Month) Value {
+	if v.Type != FiscalType || v.Fiscal == nil {
+		return v
+	}
+	from, to := v.Fiscal.Range(start)
+	return interval(&from, &to, v.Fiscal.Gran)
+}
+
+//fiscalWords are the words denoting a fiscal period
+const fiscalWords = `(?:financial|fiscal)`
+
+//relativeFiscal recognises the expressions like this financial year, last fiscal quarter
+var relativeFiscal = regexp.MustCompile(`\b(this|current|last|previous|next)\s+` + fiscalWords + `\s+(year|quarter)\b`)
+
+//namedFiscal recognises the expressions like FY19, FY 2018-19, FY2019 Q2, Q2 FY19
+var namedFiscal = regexp.MustCompile(`\b(?:q([1-4])\s+)?fy\s*'?(\d{2}|\d{4})(?:\s*-\s*(\d{2}|\d{4}))?(?:\s+q([1-4]))?\b`)
+
+//fiscalYearNamed recognises the expressions like financial year 2019, fiscal year 2018-19
+var fiscalYearNamed = regexp.MustCompile(`\b` + fiscalWords + `\s+year\s+(\d{4})(?:\s*-\s*(\d{2}|\d{4}))?\b`)
+
+//ParseFiscal will find the fiscal periods in the query. The relative periods are resolved against the given reference time
+func ParseFiscal(query []rune, now time.Time) Results {
+	/*
+	 * We will find the relative fiscal periods
+	 * Then we will find the named fiscal periods
+	 * Then we will convert the byte offsets to rune offsets and build the response
+	 */
+	s := normalise(query)
+	spans := []span{}
+
+	//relative periods
+	for _, m := range relativeFiscal.FindAllStringSubmatchIndex(s, -1) {
+		f := FiscalPeriod{Gran: s[m[4]:m[5]], RefTime: now}
+		switch s[m[2]:m[3]] {
+		case "last", "previous":
+			f.Offset = -1
+		case "next":
+			f.Offset = 1
+		}
+		spans = append(spans, span{start: m[0], end: m[1], value: Value{Type: FiscalType, Value: s[m[0]:m[1]], Gran: f.Gran, Fiscal: &f}})
+	}
+
+	//named periods
+	for _, m := range namedFiscal.FindAllStringSubmatchIndex(s, -1) {
+		if overlaps(spans, m[0], m[1]) {
+			continue
+		}
+		f := FiscalPeriod{Year: fiscalYear(s, m[4], m[5], m[6], m[7]), Gran: GrainYear, RefTime: now}
+		for _, g := range []int{2, 8} {
+			if m[g] >= 0 {
+				f.Quarter, _ = strconv.Atoi(s[m[g]:m[g+1]])
+				f.Gran = GrainQuarter
+			}
+		}
+		spans = append(spans, span{start: m[0], end: m[1], value: Value{Type: FiscalType, Value: s[m[0]:m[1]], Gran: f.Gran, Fiscal: &f}})
+	}
+	for _, m := range fiscalYearNamed.FindAllStringSubmatchIndex(s, -1) {
+		if overlaps(spans, m[0], m[1]) {
+			continue
+		}
+		f := FiscalPeriod{Year: fiscalYear(s, m[2], m[3], m[4], m[5]), Gran: GrainYear, RefTime: now}
+		spans = append(spans, span{start: m[0], end: m[1], value: Value{Type: FiscalType, Value: s[m[0]:m[1]], Gran: f.Gran, Fiscal: &f}})
+	}
+
+	//building the response
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	result := Results{Res: []Response{}}
+	for _, sp := range spans {
+		start := utf8.RuneCountInString(s[:sp.start])
+		end := start + utf8.RuneCountInString(s[sp.start:sp.end])
+		result.Res = append(result.Res, Response{Start: start, End: end, Dim: "time", Body: string(query[start:end]), Value: sp.value})
+	}
+	return result
+}
+
+//fiscalYear returns the year in which the fiscal year ends from the matched year and the optional end year.
+//Two digit years are considered to be in this century
+func fiscalYear(s string, start, end, toStart, toEnd int) int {
+	if toStart >= 0 {
+		start, end = toStart, toEnd
+	}
+	year, _ := strconv.Atoi(s[start:end])
+	if end-start == 2 {
+		year += 2000
+	}
+	return year
+}
+
+//Merge returns the responses of the results along with the responses of the other results not overlapping them.
+//So the responses of the results are given priority over the other results
+func (r Results) Merge(other Results) Results {
+	result := Results{Res: append([]Response{}, r.Res...), Err: r.Err}
+	for _, o := range other.Res {
+		overlapping := false
+		for _, res := range r.Res {
+			if o.Start < res.End && res.Start < o.End {
+				overlapping = true
+				break
+			}
+		}
+		if !overlapping {
+			result.Res = append(result.Res, o)
+		}
+	}
+	if result.Err == nil {
+		result.Err = other.Err
+	}
+	return result
+}
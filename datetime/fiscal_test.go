@@ -0,0 +1,91 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package datetime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cuttle-ai/octopus/testutils"
+)
+
+/*
+ * This file contains the tests for interpreting the fiscal periods
+ */
+
+type fiscalTest struct {
+	testutils.Test
+	Input string
+	Body  string
+	Start time.Month
+	From  time.Time
+	To    time.Time
+}
+
+var fiscalTestcases = []fiscalTest{
+	{
+		Test:  testutils.Test{Name: "This financial year april", Description: "this financial year starting in april"},
+		Input: "sales this financial year", Body: "this financial year", Start: time.April,
+		From: time.Date(2019, time.April, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Test:  testutils.Test{Name: "Last fiscal year july", Description: "last fiscal year starting in july"},
+		Input: "sales last fiscal year", Body: "last fiscal year", Start: time.July,
+		From: time.Date(2017, time.July, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2018, time.July, 1, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Test:  testutils.Test{Name: "This fiscal quarter", Description: "may is in the first quarter of the fiscal year starting in april"},
+		Input: "sales this fiscal quarter", Body: "this fiscal quarter", Start: time.April,
+		From: time.Date(2019, time.April, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2019, time.July, 1, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Test:  testutils.Test{Name: "FY with quarter", Description: "FY19 Q2 is the second quarter of the fiscal year ending in 2019"},
+		Input: "sales in FY19 Q2", Body: "FY19 Q2", Start: time.April,
+		From: time.Date(2018, time.July, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2018, time.October, 1, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Test:  testutils.Test{Name: "FY range", Description: "FY 2018-19 is named by the year in which it ends"},
+		Input: "sales in FY 2018-19", Body: "FY 2018-19", Start: time.April,
+		From: time.Date(2018, time.April, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2019, time.April, 1, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Test:  testutils.Test{Name: "Calendar fiscal year", Description: "fiscal year starting in january is the calendar year"},
+		Input: "sales for financial year 2019", Body: "financial year 2019", Start: time.January,
+		From: time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC),
+	},
+}
+
+func TestParseFiscal(t *testing.T) {
+	for _, v := range fiscalTestcases {
+		t.Run(v.Name, func(t *testing.T) {
+			res := ParseFiscal([]rune(v.Input), testNow)
+			if len(res.Res) != 1 {
+				t.Fatal("Expected one fiscal period. Got", res.Res)
+			}
+			if res.Res[0].Body != v.Body || !res.Res[0].IsValid() {
+				t.Error("Expected a valid fiscal period", v.Body, "got", res.Res[0])
+			}
+			value := res.Res[0].Value.ResolveFiscal(v.Start)
+			from, to := value.Bounds()
+			if from == nil || !from.Equal(v.From) || to == nil || !to.Equal(v.To) {
+				t.Error("Expected the range", v.From, v.To, "got", from, to)
+			}
+		})
+	}
+}
+
+func TestMergeResults(t *testing.T) {
+	fiscal := ParseFiscal([]rune("sales this financial year till today"), testNow)
+	res := fiscal.Merge(Parse([]rune("sales this financial year till today"), testNow))
+	if len(res.Res) != 2 || res.Res[0].Value.Type != FiscalType {
+		t.Error("Expected the fiscal period to be given priority over the overlapping results. Got", res.Res)
+	}
+}
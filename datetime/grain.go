@@ -0,0 +1,89 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package datetime
+
+import "time"
+
+/*
+ * This file contains the utilities for expanding a time value to the range of its grain
+ */
+
+const (
+	//GrainSecond is the grain of a second
+	GrainSecond = "second"
+	//GrainMinute is the grain of a minute
+	GrainMinute = "minute"
+	//GrainHour is the grain of an hour
+	GrainHour = "hour"
+	//GrainDay is the grain of a day
+	GrainDay = "day"
+	//GrainWeek is the grain of a week
+	GrainWeek = "week"
+	//GrainMonth is the grain of a month
+	GrainMonth = "month"
+	//GrainQuarter is the grain of a quarter
+	GrainQuarter = "quarter"
+	//GrainYear is the grain of a year
+	GrainYear = "year"
+)
+
+//GrainEnd returns the exclusive end of the period of the given grain starting at t.
+//If the grain is not known it will return false
+func GrainEnd(t time.Time, gran string) (time.Time, bool) {
+	switch gran {
+	case GrainSecond:
+		return t.Add(time.Second), true
+	case GrainMinute:
+		return t.Add(time.Minute), true
+	case GrainHour:
+		return t.Add(time.Hour), true
+	case GrainDay:
+		return t.AddDate(0, 0, 1), true
+	case GrainWeek:
+		return t.AddDate(0, 0, 7), true
+	case GrainMonth:
+		return t.AddDate(0, 1, 0), true
+	case GrainQuarter:
+		return t.AddDate(0, 3, 0), true
+	case GrainYear:
+		return t.AddDate(1, 0, 0), true
+	}
+	return t, false
+}
+
+//Bounds returns the half open range [from, to) denoted by the value.
+//For a value with a grain, the range spans the whole period of the grain. Like the entire month for this month.
+//For an interval, the range is the from and to of the interval. Either of them can be nil for an open interval.
+//A fiscal period is considered to be in the calendar year. Use ResolveFiscal for the fiscal calendar.
+//If the value is not valid, both of them will be nil
+func (v *Value) Bounds() (from, to *time.Time) {
+	/*
+	 * If the value is not valid we will return
+	 * If the type is fiscal, we will return the range in the calendar year
+	 * If the type is value, we will expand the time to the end of its grain
+	 * If the type is interval, we will return the valid from and to
+	 */
+	if !v.IsValid() {
+		return nil, nil
+	}
+	if v.Type == FiscalType {
+		f, t := v.Fiscal.Range(time.January)
+		return &f, &t
+	}
+	if v.Type == "value" {
+		end, ok := GrainEnd(*v.Time, v.Gran)
+		if !ok {
+			return v.Time, nil
+		}
+		return v.Time, &end
+	}
+	if v.From != nil && v.From.IsValid() {
+		from = v.From.Time
+	}
+	if v.To != nil && v.To.IsValid() {
+		to = v.To.Time
+	}
+	return from, to
+}
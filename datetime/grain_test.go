@@ -0,0 +1,83 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package datetime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cuttle-ai/octopus/testutils"
+)
+
+/*
+ * This file contains the tests for expanding the time values to the range of their grain
+ */
+
+type boundsTest struct {
+	testutils.Test
+	Input Value
+	From  time.Time
+	To    time.Time
+}
+
+var boundsTestcases = []boundsTest{
+	{
+		Test:  testutils.Test{Name: "Day", Description: "today spans the whole day"},
+		Input: Value{Type: "value", Value: "2019-05-21T00:00:00.000+05:30", Gran: GrainDay},
+		From:  time.Date(2019, time.May, 21, 0, 0, 0, 0, time.FixedZone("", 19800)),
+		To:    time.Date(2019, time.May, 22, 0, 0, 0, 0, time.FixedZone("", 19800)),
+	},
+	{
+		Test:  testutils.Test{Name: "Week", Description: "this week spans seven days"},
+		Input: Value{Type: "value", Value: "2019-05-20T00:00:00.000+05:30", Gran: GrainWeek},
+		From:  time.Date(2019, time.May, 20, 0, 0, 0, 0, time.FixedZone("", 19800)),
+		To:    time.Date(2019, time.May, 27, 0, 0, 0, 0, time.FixedZone("", 19800)),
+	},
+	{
+		Test:  testutils.Test{Name: "Month", Description: "this month spans till the start of next month"},
+		Input: Value{Type: "value", Value: "2019-02-01T00:00:00.000+05:30", Gran: GrainMonth},
+		From:  time.Date(2019, time.February, 1, 0, 0, 0, 0, time.FixedZone("", 19800)),
+		To:    time.Date(2019, time.March, 1, 0, 0, 0, 0, time.FixedZone("", 19800)),
+	},
+	{
+		Test:  testutils.Test{Name: "Quarter", Description: "a quarter spans three months"},
+		Input: Value{Type: "value", Value: "2019-10-01T00:00:00.000+05:30", Gran: GrainQuarter},
+		From:  time.Date(2019, time.October, 1, 0, 0, 0, 0, time.FixedZone("", 19800)),
+		To:    time.Date(2020, time.January, 1, 0, 0, 0, 0, time.FixedZone("", 19800)),
+	},
+	{
+		Test:  testutils.Test{Name: "Year", Description: "a year spans till the start of next year"},
+		Input: Value{Type: "value", Value: "2019-01-01T00:00:00.000+05:30", Gran: GrainYear},
+		From:  time.Date(2019, time.January, 1, 0, 0, 0, 0, time.FixedZone("", 19800)),
+		To:    time.Date(2020, time.January, 1, 0, 0, 0, 0, time.FixedZone("", 19800)),
+	},
+	{
+		Test: testutils.Test{Name: "Interval", Description: "an interval spans from its from to its to"},
+		Input: Value{
+			Type: "interval",
+			From: &TimeValue{Value: "2019-01-01T00:00:00.000+05:30", Gran: GrainDay},
+			To:   &TimeValue{Value: "2019-01-08T00:00:00.000+05:30", Gran: GrainDay},
+		},
+		From: time.Date(2019, time.January, 1, 0, 0, 0, 0, time.FixedZone("", 19800)),
+		To:   time.Date(2019, time.January, 8, 0, 0, 0, 0, time.FixedZone("", 19800)),
+	},
+}
+
+func TestBounds(t *testing.T) {
+	for _, v := range boundsTestcases {
+		t.Run(v.Name, func(t *testing.T) {
+			from, to := v.Input.Bounds()
+			if from == nil || to == nil {
+				t.Fatal("Expected both the bounds. Got", from, to)
+			}
+			if !from.Equal(v.From) {
+				t.Error("Expected from", v.From, "got", *from)
+			}
+			if !to.Equal(v.To) {
+				t.Error("Expected to", v.To, "got", *to)
+			}
+		})
+	}
+}
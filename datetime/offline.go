@@ -0,0 +1,339 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package datetime
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+/*
+ * This file contains the implementation of a rule based offline datetime service
+ */
+
+//ValueFormat is the format in which the time values are returned by the services
+const ValueFormat = "2006-01-02T15:04:05.000-07:00"
+
+//Offline is a rule based datetime service which doesn't require any external server.
+//It can recognise the common english expressions like today, last week, this month, Q3 2019,
+//since January, ISO dates, last 3 years etc.
+type Offline struct {
+	//Now returns the reference time against which the relative expressions are resolved if the options doesn't have one
+	Now func() time.Time
+}
+
+//NewOffline returns an offline datetime service resolving the expressions against the current time
+func NewOffline() *Offline {
+	return &Offline{Now: time.Now}
+}
+
+//Query returns a channel of Response. Which will return the reponse in an concurrent environment
+//The expressions are resolved against the reference time of the options in its timezone
+func (o *Offline) Query(ctx context.Context, query []rune, opts Options) chan Results {
+	ch := make(chan Results, 1)
+	if ctx.Err() != nil {
+		ch <- Results{Err: ctx.Err()}
+		return ch
+	}
+	if opts.RefTime.IsZero() && o.Now != nil {
+		opts.RefTime = o.Now()
+	}
+	now, err := opts.Now()
+	if err != nil {
+		ch <- Results{Err: err}
+		return ch
+	}
+	ch <- Parse(query, now)
+	return ch
+}
+
+//span is a time expression found in the query
+type span struct {
+	//start and end are the byte offsets of the expression in the lowercased query
+	start int
+	end   int
+	value Value
+}
+
+//point is the start of a period with its grain
+type point struct {
+	t    time.Time
+	gran string
+}
+
+//pointMatcher recognises an expression denoting a period of time
+type pointMatcher struct {
+	re *regexp.Regexp
+	//group is the sub match denoting the expression. 0 for the whole match
+	group   int
+	resolve func(m []string, now time.Time) (point, bool)
+}
+
+//months has the month number of the month names
+var months = map[string]time.Month{
+	"january": time.January, "jan": time.January,
+	"february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"may":  time.May,
+	"june": time.June, "jun": time.June,
+	"july": time.July, "jul": time.July,
+	"august": time.August, "aug": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September,
+	"october": time.October, "oct": time.October,
+	"november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+}
+
+const monthNames = `january|february|march|april|may|june|july|august|september|october|november|december|jan|feb|mar|apr|jun|jul|aug|sept|sep|oct|nov|dec`
+
+const grainNames = `day|week|month|quarter|year`
+
+//pointMatchers are the matchers for the expressions in the order of their priority
+var pointMatchers = []pointMatcher{
+	{
+		re: regexp.MustCompile(`\b(\d{4})-(\d{2})-(\d{2})\b`),
+		resolve: func(m []string, now time.Time) (point, bool) {
+			t, err := time.ParseInLocation("2006-01-02", m[1]+"-"+m[2]+"-"+m[3], now.Location())
+			if err != nil {
+				return point{}, false
+			}
+			return point{t, GrainDay}, true
+		},
+	},
+	{
+		re: regexp.MustCompile(`\bq([1-4])(?:\s+(\d{4}))?\b`),
+		resolve: func(m []string, now time.Time) (point, bool) {
+			q, _ := strconv.Atoi(m[1])
+			year := now.Year()
+			if len(m[2]) > 0 {
+				year, _ = strconv.Atoi(m[2])
+			}
+			return point{time.Date(year, time.Month((q-1)*3+1), 1, 0, 0, 0, 0, now.Location()), GrainQuarter}, true
+		},
+	},
+	{
+		re: regexp.MustCompile(`\b(this|current|last|previous|next)\s+(` + grainNames + `)\b`),
+		resolve: func(m []string, now time.Time) (point, bool) {
+			t := GrainStart(now, m[2])
+			switch m[1] {
+			case "last", "previous":
+				t = shift(t, m[2], -1)
+			case "next":
+				t = shift(t, m[2], 1)
+			}
+			return point{t, m[2]}, true
+		},
+	},
+	{
+		re: regexp.MustCompile(`\b(today|yesterday|tomorrow)\b`),
+		resolve: func(m []string, now time.Time) (point, bool) {
+			t := GrainStart(now, GrainDay)
+			switch m[1] {
+			case "yesterday":
+				t = t.AddDate(0, 0, -1)
+			case "tomorrow":
+				t = t.AddDate(0, 0, 1)
+			}
+			return point{t, GrainDay}, true
+		},
+	},
+	{
+		re: regexp.MustCompile(`\b(` + monthNames + `)(?:\s+(\d{4}))?\b`),
+		resolve: func(m []string, now time.Time) (point, bool) {
+			month := months[m[1]]
+			if len(m[2]) == 0 && m[1] == "may" {
+				//may without a year is more likely to be the verb
+				return point{}, false
+			}
+			if len(m[2]) > 0 {
+				year, _ := strconv.Atoi(m[2])
+				return point{time.Date(year, month, 1, 0, 0, 0, 0, now.Location()), GrainMonth}, true
+			}
+			//without a year, the latest occurrence of the month which is not in the future is taken
+			t := time.Date(now.Year(), month, 1, 0, 0, 0, 0, now.Location())
+			if t.After(now) {
+				t = t.AddDate(-1, 0, 0)
+			}
+			return point{t, GrainMonth}, true
+		},
+	},
+	{
+		re:    regexp.MustCompile(`\b(?:in|of|during|year|since|from|after|till|until|upto|before)\s+((?:19|20)\d{2})\b`),
+		group: 1,
+		resolve: func(m []string, now time.Time) (point, bool) {
+			year, _ := strconv.Atoi(m[1])
+			return point{time.Date(year, time.January, 1, 0, 0, 0, 0, now.Location()), GrainYear}, true
+		},
+	},
+}
+
+//durationMatcher recognises the expressions like last 3 years
+var durationMatcher = regexp.MustCompile(`\b(last|past|previous|next)\s+(\d+)\s+(` + grainNames + `)s?\b`)
+
+//sincePrefix and tillPrefix are the words preceding an expression making it an open interval
+var sincePrefix = regexp.MustCompile(`\b(since|from|after)\s+$`)
+var tillPrefix = regexp.MustCompile(`\b(till|until|upto|up to|before)\s+$`)
+
+//Parse will find the time expressions in the query resolved against the given reference time
+func Parse(query []rune, now time.Time) Results {
+	/*
+	 * We will lower case the query without changing the rune offsets
+	 * Then we will find the durations and the other expressions in the order of priority skipping the overlaps
+	 * Expressions preceded by since/till words are converted to intervals
+	 * Then we will convert the byte offsets to rune offsets and build the response
+	 */
+	s := normalise(query)
+	spans := []span{}
+
+	//finding the durations
+	for _, m := range durationMatcher.FindAllStringSubmatchIndex(s, -1) {
+		n, err := strconv.Atoi(s[m[4]:m[5]])
+		if err != nil || n <= 0 {
+			continue
+		}
+		gran := s[m[6]:m[7]]
+		from := GrainStart(now, gran)
+		to := from
+		if s[m[2]:m[3]] == "next" {
+			from = shift(from, gran, 1)
+			to = shift(from, gran, n)
+		} else {
+			from = shift(from, gran, -n)
+		}
+		spans = append(spans, span{start: m[0], end: m[1], value: interval(&from, &to, gran)})
+	}
+
+	//finding the other expressions
+	for _, pm := range pointMatchers {
+		for _, m := range pm.re.FindAllStringSubmatchIndex(s, -1) {
+			start, end := m[2*pm.group], m[2*pm.group+1]
+			if overlaps(spans, start, end) {
+				continue
+			}
+			sub := make([]string, len(m)/2)
+			for i := range sub {
+				if m[2*i] >= 0 {
+					sub[i] = s[m[2*i]:m[2*i+1]]
+				}
+			}
+			p, ok := pm.resolve(sub, now)
+			if !ok {
+				continue
+			}
+			sp := span{start: start, end: end, value: value(p.t, p.gran)}
+
+			//checking for the since and till prefixes
+			if loc := sincePrefix.FindStringSubmatchIndex(s[:start]); loc != nil && !overlaps(spans, loc[0], start) {
+				sp.start = loc[0]
+				from := p.t
+				if s[loc[2]:loc[3]] == "after" {
+					from, _ = GrainEnd(p.t, p.gran)
+				}
+				sp.value = interval(&from, nil, p.gran)
+			} else if loc := tillPrefix.FindStringSubmatchIndex(s[:start]); loc != nil && !overlaps(spans, loc[0], start) {
+				sp.start = loc[0]
+				to := p.t
+				if s[loc[2]:loc[3]] != "before" {
+					to, _ = GrainEnd(p.t, p.gran)
+				}
+				sp.value = interval(nil, &to, p.gran)
+			}
+			spans = append(spans, sp)
+		}
+	}
+
+	//building the response
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	result := Results{Res: []Response{}}
+	for _, sp := range spans {
+		start := utf8.RuneCountInString(s[:sp.start])
+		end := start + utf8.RuneCountInString(s[sp.start:sp.end])
+		result.Res = append(result.Res, Response{
+			Start: start,
+			End:   end,
+			Dim:   "time",
+			Body:  string(query[start:end]),
+			Value: sp.value,
+		})
+	}
+	return result
+}
+
+//overlaps returns true if the given range overlaps with any of the spans
+func overlaps(spans []span, start, end int) bool {
+	for _, sp := range spans {
+		if start < sp.end && sp.start < end {
+			return true
+		}
+	}
+	return false
+}
+
+//value returns the time value for the given time and grain
+func value(t time.Time, gran string) Value {
+	return Value{Type: "value", Value: t.Format(ValueFormat), Gran: gran}
+}
+
+//interval returns the interval time value with the given from and to. Either of them can be nil
+func interval(from, to *time.Time, gran string) Value {
+	v := Value{Type: "interval"}
+	if from != nil {
+		v.From = &TimeValue{Value: from.Format(ValueFormat), Gran: gran}
+	}
+	if to != nil {
+		v.To = &TimeValue{Value: to.Format(ValueFormat), Gran: gran}
+	}
+	return v
+}
+
+//GrainStart returns the start of the period of the given grain in which t falls. Weeks start on monday.
+//If the grain is not known, t is returned as it is
+func GrainStart(t time.Time, gran string) time.Time {
+	switch gran {
+	case GrainSecond:
+		return t.Truncate(time.Second)
+	case GrainMinute:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, t.Location())
+	case GrainHour:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	case GrainDay:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	case GrainWeek:
+		d := GrainStart(t, GrainDay)
+		return d.AddDate(0, 0, -((int(d.Weekday()) + 6) % 7))
+	case GrainMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	case GrainQuarter:
+		return time.Date(t.Year(), ((t.Month()-1)/3)*3+1, 1, 0, 0, 0, 0, t.Location())
+	case GrainYear:
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+	}
+	return t
+}
+
+//shift moves t by n periods of the given grain and snaps it to the start of the period.
+//n can be negative to move t back. If the grain is not known, t is returned as it is
+func shift(t time.Time, gran string, n int) time.Time {
+	switch gran {
+	case GrainDay:
+		t = t.AddDate(0, 0, n)
+	case GrainWeek:
+		t = t.AddDate(0, 0, 7*n)
+	case GrainMonth:
+		t = t.AddDate(0, n, 0)
+	case GrainQuarter:
+		t = t.AddDate(0, 3*n, 0)
+	case GrainYear:
+		t = t.AddDate(n, 0, 0)
+	default:
+		return t
+	}
+	return GrainStart(t, gran)
+}
@@ -0,0 +1,201 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package datetime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cuttle-ai/octopus/testutils"
+)
+
+/*
+ * This file contains the tests for the offline datetime service
+ */
+
+//testNow is a tuesday
+var testNow = time.Date(2019, time.May, 21, 10, 30, 0, 0, time.UTC)
+
+type offlineTest struct {
+	testutils.Test
+	Input string
+	Body  string
+	Start int
+	Gran  string
+	From  time.Time
+	To    time.Time
+}
+
+var offlineTestcases = []offlineTest{
+	{
+		Test:  testutils.Test{Name: "Today", Description: "today is resolved to the reference day"},
+		Input: "sales today", Body: "today", Start: 6, Gran: GrainDay,
+		From: time.Date(2019, time.May, 21, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2019, time.May, 22, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Test:  testutils.Test{Name: "Yesterday", Description: "yesterday is resolved to the day before"},
+		Input: "Sales Yesterday", Body: "Yesterday", Start: 6, Gran: GrainDay,
+		From: time.Date(2019, time.May, 20, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2019, time.May, 21, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Test:  testutils.Test{Name: "Last week", Description: "last week starts on the previous monday"},
+		Input: "sales last week", Body: "last week", Start: 6, Gran: GrainWeek,
+		From: time.Date(2019, time.May, 13, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2019, time.May, 20, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Test:  testutils.Test{Name: "This month", Description: "this month spans the whole month"},
+		Input: "sales this month", Body: "this month", Start: 6, Gran: GrainMonth,
+		From: time.Date(2019, time.May, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2019, time.June, 1, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Test:  testutils.Test{Name: "Quarter with year", Description: "Q3 2019 spans july to september"},
+		Input: "sales in Q3 2019", Body: "Q3 2019", Start: 9, Gran: GrainQuarter,
+		From: time.Date(2019, time.July, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2019, time.October, 1, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Test:  testutils.Test{Name: "Since month", Description: "since january is an interval from the last january"},
+		Input: "sales since January", Body: "since January", Start: 6, Gran: GrainMonth,
+		From: time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Test:  testutils.Test{Name: "After year", Description: "after a year is an interval from the end of the year"},
+		Input: "sales after 2019", Body: "after 2019", Start: 6, Gran: GrainYear,
+		From: time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Test:  testutils.Test{Name: "After month", Description: "after a month is an interval from the end of the month"},
+		Input: "sales after march", Body: "after march", Start: 6, Gran: GrainMonth,
+		From: time.Date(2019, time.April, 1, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Test:  testutils.Test{Name: "Before year", Description: "before a year is an interval till the start of the year"},
+		Input: "sales before 2019", Body: "before 2019", Start: 6, Gran: GrainYear,
+		To: time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Test:  testutils.Test{Name: "ISO date", Description: "iso dates are resolved to the day"},
+		Input: "sales on 2019-02-14", Body: "2019-02-14", Start: 9, Gran: GrainDay,
+		From: time.Date(2019, time.February, 14, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2019, time.February, 15, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Test:  testutils.Test{Name: "Last n years", Description: "last 3 years are the three years before this year"},
+		Input: "sales in the last 3 years", Body: "last 3 years", Start: 13, Gran: GrainYear,
+		From: time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Test:  testutils.Test{Name: "Next n quarters", Description: "next 2 quarters are the two quarters after this quarter"},
+		Input: "sales in the next 2 quarters", Body: "next 2 quarters", Start: 13, Gran: GrainQuarter,
+		From: time.Date(2019, time.July, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Test:  testutils.Test{Name: "Last n weeks", Description: "last 2 weeks are the two weeks before this week"},
+		Input: "sales in the last 2 weeks", Body: "last 2 weeks", Start: 13, Gran: GrainWeek,
+		From: time.Date(2019, time.May, 6, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2019, time.May, 20, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Test:  testutils.Test{Name: "Year", Description: "a year preceded by in is resolved to the year"},
+		Input: "sales in 2018", Body: "2018", Start: 9, Gran: GrainYear,
+		From: time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Test:  testutils.Test{Name: "Till", Description: "till a month is an interval till the end of the month"},
+		Input: "sales till March 2019", Body: "till March 2019", Start: 6, Gran: GrainMonth,
+		To: time.Date(2019, time.April, 1, 0, 0, 0, 0, time.UTC),
+	},
+}
+
+func TestOffline(t *testing.T) {
+	o := &Offline{Now: func() time.Time { return testNow }}
+	for _, v := range offlineTestcases {
+		t.Run(v.Name, func(t *testing.T) {
+			res := <-o.Query(context.Background(), []rune(v.Input), Options{})
+			if len(res.Res) != 1 {
+				t.Fatal("Expected one result. Got", res.Res)
+			}
+			r := res.Res[0]
+			if !r.IsValid() {
+				t.Fatal("Expected a valid response. Got", r)
+			}
+			if r.Body != v.Body || r.Start != v.Start || r.End != v.Start+len([]rune(v.Body)) {
+				t.Error("Expected", v.Body, "at", v.Start, "got", r.Body, "at", r.Start, r.End)
+			}
+			if r.Value.Gran != v.Gran && (r.Value.From == nil || r.Value.From.Gran != v.Gran) && (r.Value.To == nil || r.Value.To.Gran != v.Gran) {
+				t.Error("Expected the grain", v.Gran, "got", r.Value)
+			}
+			from, to := r.Value.Bounds()
+			if (from == nil) != v.From.IsZero() || (from != nil && !from.Equal(v.From)) {
+				t.Error("Expected from", v.From, "got", from)
+			}
+			if (to == nil) != v.To.IsZero() || (to != nil && !to.Equal(v.To)) {
+				t.Error("Expected to", v.To, "got", to)
+			}
+		})
+	}
+}
+
+type shiftTest struct {
+	testutils.Test
+	Gran     string
+	N        int
+	Expected time.Time
+}
+
+var shiftTestcases = []shiftTest{
+	{Test: testutils.Test{Name: "Days back", Description: "large counts from the user are shifted in one step"}, Gran: GrainDay, N: -99999999, Expected: time.Date(2019, time.May, 21, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -99999999)},
+	{Test: testutils.Test{Name: "Weeks", Description: "weeks are shifted by seven days and start on monday"}, Gran: GrainWeek, N: 3, Expected: time.Date(2019, time.June, 10, 0, 0, 0, 0, time.UTC)},
+	{Test: testutils.Test{Name: "Months", Description: "months are shifted to the start of the month"}, Gran: GrainMonth, N: -5, Expected: time.Date(2018, time.December, 1, 0, 0, 0, 0, time.UTC)},
+	{Test: testutils.Test{Name: "Quarters", Description: "quarters are shifted by three months"}, Gran: GrainQuarter, N: 3, Expected: time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	{Test: testutils.Test{Name: "Years", Description: "years are shifted to the start of the year"}, Gran: GrainYear, N: 1000000, Expected: time.Date(1002019, time.January, 1, 0, 0, 0, 0, time.UTC)},
+}
+
+func TestShift(t *testing.T) {
+	for _, v := range shiftTestcases {
+		t.Run(v.Name, func(t *testing.T) {
+			if got := shift(testNow, v.Gran, v.N); !got.Equal(v.Expected) {
+				t.Error("Expected", v.Expected, "got", got)
+			}
+		})
+	}
+}
+
+func TestOfflineNoTime(t *testing.T) {
+	res := Parse([]rune("what may be the top 1000 brands"), testNow)
+	if len(res.Res) != 0 {
+		t.Error("Expected no time expressions. Got", res.Res)
+	}
+}
+
+func TestOfflineOptions(t *testing.T) {
+	o := NewOffline()
+	ist, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skip("timezone database is not available", err)
+	}
+
+	//20:00 UTC is already the next day in india
+	res := <-o.Query(context.Background(), []rune("sales today"), Options{RefTime: time.Date(2019, time.May, 21, 20, 0, 0, 0, time.UTC), Timezone: "Asia/Kolkata"})
+	if len(res.Res) != 1 || !res.Res[0].IsValid() {
+		t.Fatal("Expected a valid result. Got", res.Res)
+	}
+	if expected := time.Date(2019, time.May, 22, 0, 0, 0, 0, ist); !res.Res[0].Value.Time.Equal(expected) {
+		t.Error("Expected today to be resolved in the timezone of the options", expected, "got", res.Res[0].Value.Time)
+	}
+
+	res = <-o.Query(context.Background(), []rune("sales today"), Options{Timezone: "Mars/Olympus"})
+	if res.Err == nil {
+		t.Error("Expected an error for an invalid timezone. Got", res.Res)
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package datetime
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+ * This file contains the registry of the datetime services and the chain of services falling back to the next one
+ */
+
+const (
+	//DucklingService is the name with which the duckling service is registered
+	DucklingService = "duckling"
+	//OfflineService is the name with which the offline service is registered
+	OfflineService = "offline"
+)
+
+//DefaultChain is the default chain of services. Duckling is tried first and offline service is the fallback
+var DefaultChain = []string{DucklingService, OfflineService}
+
+//DefaultChainTimeout is the default time to wait for a service in the chain before falling back to the next one
+var DefaultChainTimeout = 2 * time.Second
+
+//Factory creates a new instance of a service
+type Factory func() (Service, error)
+
+var (
+	registry     = map[string]Factory{}
+	registryLock sync.RWMutex
+)
+
+func init() {
+	Register(DucklingService, func() (Service, error) { return NewDuckling() })
+	Register(OfflineService, func() (Service, error) { return NewOffline(), nil })
+}
+
+//Register registers the factory of a service with the given name. If a service already exists with the name, it is replaced
+func Register(name string, f Factory) {
+	registryLock.Lock()
+	registry[name] = f
+	registryLock.Unlock()
+}
+
+//Services returns the names of the registered services in sorted order
+func Services() []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	names := []string{}
+	for k := range registry {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//New returns a new instance of the service registered with the given name
+func New(name string) (Service, error) {
+	registryLock.RLock()
+	f, ok := registry[name]
+	registryLock.RUnlock()
+	if !ok {
+		return nil, errors.New("couldn't find the datetime service " + name + " in the registry")
+	}
+	return f()
+}
+
+//Chain is a datetime service which queries the services in order.
+//If a service errors or doesn't respond within the timeout, the next service is queried
+type Chain struct {
+	//Services are the services in the chain
+	Services []Service
+	//Timeout is the time to wait for each service
+	Timeout time.Duration
+}
+
+//NewChain returns a chain of the services registered with the given names.
+//Services which couldn't be initialized are skipped. If none of them could be initialized, an error is returned
+func NewChain(names ...string) (*Chain, error) {
+	c := &Chain{Timeout: DefaultChainTimeout}
+	for _, name := range names {
+		s, err := New(name)
+		if err != nil {
+			log.Println("skipping the datetime service", name, "in the chain.", err)
+			continue
+		}
+		c.Services = append(c.Services, s)
+	}
+	if len(c.Services) == 0 {
+		return nil, errors.New("couldn't initialize any of the datetime services " + strings.Join(names, ", "))
+	}
+	return c, nil
+}
+
+//Query returns a channel of Response. Which will return the reponse in an concurrent environment
+func (c *Chain) Query(ctx context.Context, query []rune, opts Options) chan Results {
+	ch := make(chan Results, 1)
+	go c.query(ctx, ch, query, opts)
+	return ch
+}
+
+func (c *Chain) query(ctx context.Context, ch chan Results, query []rune, opts Options) {
+	/*
+	 * We will query the services in order
+	 * If a service responds without an error, we will return its results
+	 * Else we will fall back to the next service
+	 * The services are not queried anymore once the context is done
+	 * Each service is given its own deadline and is cancelled when we fall back from it
+	 */
+	result := Results{Err: errors.New("couldn't find any datetime services in the chain")}
+	for _, s := range c.Services {
+		result = c.queryService(ctx, s, query, opts)
+		if ctx.Err() != nil {
+			ch <- Results{Err: ctx.Err()}
+			return
+		}
+		if result.Err == nil {
+			break
+		}
+		log.Println("falling back to the next datetime service in the chain.", result.Err)
+	}
+	ch <- result
+}
+
+//queryService queries the service with the timeout of the chain. The query is cancelled once the service responds or times out
+func (c *Chain) queryService(ctx context.Context, s Service, query []rune, opts Options) Results {
+	sCtx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+	select {
+	case result := <-s.Query(sCtx, query, opts):
+		return result
+	case <-sCtx.Done():
+		return Results{Err: errors.New("timed out while waiting for the datetime service")}
+	}
+}
+
+var (
+	defaultService     Service
+	defaultServiceLock sync.Mutex
+)
+
+//SetDefaultService sets the service to be returned by DefaultService
+func SetDefaultService(s Service) {
+	defaultServiceLock.Lock()
+	defaultService = s
+	defaultServiceLock.Unlock()
+}
+
+//DefaultService will return a service to provide the interpretation of datetime.
+//It is a cached chain of the services. The services in the chain can be configured with the environment variable
+//DATETIME_SERVICES as comma separated names. The time to wait for each service in milliseconds can be configured with
+//DATETIME_SERVICE_TIMEOUT and the no. of results cached with DATETIME_CACHE_SIZE
+func DefaultService() (Service, error) {
+	/*
+	 * If the default service is already initialized we will return it
+	 * Else we will create the chain from the environment variables and wrap it with a cache
+	 */
+	defaultServiceLock.Lock()
+	defer defaultServiceLock.Unlock()
+	if defaultService != nil {
+		return defaultService, nil
+	}
+
+	//services in the chain
+	names := DefaultChain
+	if len(os.Getenv("DATETIME_SERVICES")) != 0 {
+		names = []string{}
+		for _, name := range strings.Split(os.Getenv("DATETIME_SERVICES"), ",") {
+			if name = strings.TrimSpace(name); len(name) != 0 {
+				names = append(names, name)
+			}
+		}
+	}
+	chain, err := NewChain(names...)
+	if err != nil {
+		return nil, err
+	}
+
+	//timeout of each service
+	if len(os.Getenv("DATETIME_SERVICE_TIMEOUT")) != 0 {
+		if t, err := strconv.ParseInt(os.Getenv("DATETIME_SERVICE_TIMEOUT"), 10, 64); err == nil {
+			chain.Timeout = time.Duration(t * int64(time.Millisecond))
+		}
+	}
+
+	//size of the cache
+	size := DefaultCacheSize
+	if len(os.Getenv("DATETIME_CACHE_SIZE")) != 0 {
+		if s, err := strconv.Atoi(os.Getenv("DATETIME_CACHE_SIZE")); err == nil {
+			size = s
+		}
+	}
+
+	defaultService = NewCache(chain, size)
+	return defaultService, nil
+}
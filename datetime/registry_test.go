@@ -0,0 +1,150 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package datetime
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+/*
+ * This file contains the tests for the registry, chain and cache of the datetime services
+ */
+
+//testService is a datetime service for testing with a configurable behaviour
+type testService struct {
+	err       error
+	delay     time.Duration
+	calls     int32
+	cancelled int32
+}
+
+func (t *testService) Query(ctx context.Context, query []rune, opts Options) chan Results {
+	atomic.AddInt32(&t.calls, 1)
+	ch := make(chan Results, 1)
+	go func() {
+		select {
+		case <-time.After(t.delay):
+		case <-ctx.Done():
+			atomic.AddInt32(&t.cancelled, 1)
+			ch <- Results{Err: ctx.Err()}
+			return
+		}
+		if t.err != nil {
+			ch <- Results{Err: t.err}
+			return
+		}
+		now, _ := opts.Now()
+		ch <- Parse(query, now)
+	}()
+	return ch
+}
+
+func TestRegistry(t *testing.T) {
+	Register("test", func() (Service, error) { return &testService{}, nil })
+	found := false
+	for _, name := range Services() {
+		if name == "test" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected the test service to be registered. Got", Services())
+	}
+	if _, err := New("missing"); err == nil {
+		t.Error("Expected an error for a service not registered. Got none")
+	}
+	if _, err := NewChain("missing"); err == nil {
+		t.Error("Expected an error for a chain without any services. Got none")
+	}
+}
+
+func TestChainFallback(t *testing.T) {
+	cases := []struct {
+		name  string
+		first *testService
+	}{
+		{name: "error", first: &testService{err: errors.New("service is down")}},
+		{name: "timeout", first: &testService{delay: time.Second}},
+	}
+	for _, v := range cases {
+		t.Run(v.name, func(t *testing.T) {
+			fallback := &testService{}
+			c := &Chain{Services: []Service{v.first, fallback}, Timeout: 50 * time.Millisecond}
+			res := <-c.Query(context.Background(), []rune("sales this month"), Options{})
+			if res.Err != nil {
+				t.Fatal("Expected the chain to fall back. Got error", res.Err)
+			}
+			if len(res.Res) != 1 || fallback.calls != 1 {
+				t.Error("Expected the result from the fallback service. Got", res.Res)
+			}
+			if v.first.delay != 0 {
+				time.Sleep(10 * time.Millisecond)
+				if atomic.LoadInt32(&v.first.cancelled) != 1 {
+					t.Error("Expected the query of the timed out service to be cancelled")
+				}
+			}
+		})
+	}
+}
+
+func TestCache(t *testing.T) {
+	s := &testService{}
+	c := NewCache(s, 1)
+	c.Now = func() time.Time { return testNow }
+
+	res := <-c.Query(context.Background(), []rune("sales this month"), Options{})
+	if len(res.Res) != 1 {
+		t.Fatal("Expected a result. Got", res.Res)
+	}
+	res = <-c.Query(context.Background(), []rune("Sales THIS Month"), Options{})
+	if len(res.Res) != 1 || res.Res[0].Body != "THIS Month" {
+		t.Fatal("Expected the cached result with the body from the query. Got", res.Res)
+	}
+	if s.calls != 1 {
+		t.Error("Expected the service to be called once. Got", s.calls)
+	}
+
+	//a different reference time is not a cache hit
+	c.Now = func() time.Time { return testNow.Add(time.Hour) }
+	<-c.Query(context.Background(), []rune("sales this month"), Options{})
+	if s.calls != 2 {
+		t.Error("Expected the service to be called again for a different reference time. Got", s.calls)
+	}
+	if c.Len() != 1 {
+		t.Error("Expected the cache to evict the oldest result. Got", c.Len(), "results")
+	}
+
+	//a different timezone is not a cache hit
+	<-c.Query(context.Background(), []rune("sales this month"), Options{Timezone: "UTC"})
+	if s.calls != 3 {
+		t.Error("Expected the service to be called again for a different timezone. Got", s.calls)
+	}
+
+	//errors are not cached
+	failing := NewCache(&testService{err: errors.New("service is down")}, 10)
+	<-failing.Query(context.Background(), []rune("sales this month"), Options{})
+	if failing.Len() != 0 {
+		t.Error("Expected the errored results not to be cached. Got", failing.Len(), "results")
+	}
+}
+
+func TestChainCancel(t *testing.T) {
+	s := &testService{delay: 5 * time.Second}
+	c := &Chain{Services: []Service{s, &testService{}}, Timeout: 10 * time.Second}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	res := <-c.Query(ctx, []rune("sales this month"), Options{})
+	if res.Err != context.Canceled {
+		t.Error("Expected the error of the context. Got", res.Err, res.Res)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("Expected the chain to give up without waiting for the services. Took", time.Since(start))
+	}
+}
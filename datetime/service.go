@@ -5,7 +5,10 @@
 //Package datetime has the utilities required for interpreting date/time from a nlp query
 package datetime
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 //TimeValue is the Value of the time stored
 type TimeValue struct {
@@ -35,6 +38,8 @@ type Value struct {
 	Time *time.Time `json:"-"`
 	//Error will be set once isvalid is run if parsing failed
 	Error error `json:"-"`
+	//Fiscal is the fiscal period if the type of the value is fiscal
+	Fiscal *FiscalPeriod `json:"fiscal,omitempty"`
 }
 
 //Response is the response of the package for external use
@@ -55,11 +60,43 @@ type Response struct {
 type Results struct {
 	//Res has the list of response
 	Res []Response
+	//Err is the error occurred while the service was querying. Results with error are not cached
+	Err error `json:"-"`
 }
 
-//Service interface produces the querying service
+//Options are the options with which the date/time in a query is interpreted
+type Options struct {
+	//RefTime is the reference time against which the relative expressions like today are resolved.
+	//If not set, the current time is used
+	RefTime time.Time
+	//Locale is the locale of the query like en_US
+	Locale string
+	//Timezone is the IANA timezone like Asia/Kolkata in which the query is interpreted.
+	//If not set, the timezone of the reference time is used
+	Timezone string
+}
+
+//Now returns the reference time of the options in its timezone.
+//If the timezone is not a valid IANA timezone, an error is returned
+func (o Options) Now() (time.Time, error) {
+	now := o.RefTime
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if len(o.Timezone) == 0 {
+		return now, nil
+	}
+	loc, err := time.LoadLocation(o.Timezone)
+	if err != nil {
+		return now, err
+	}
+	return now.In(loc), nil
+}
+
+//Service interface produces the querying service.
+//The services give up the query once the context is done
 type Service interface {
-	Query(query []rune) chan Results
+	Query(ctx context.Context, query []rune, opts Options) chan Results
 }
 
 //IsValid checks whether the time value is valid or not
@@ -104,6 +141,9 @@ func (v *Value) IsValid() bool {
 		v.Time = &t
 		return true
 	}
+	if v.Type == FiscalType {
+		return v.Fiscal != nil
+	}
 	if v.Type == "interval" {
 		if v.From == nil && v.To == nil {
 			return false
@@ -135,8 +175,3 @@ func (r *Results) IsValid() bool {
 	}
 	return one
 }
-
-//DefaultService will return a service to provide the interpretation of datetime
-func DefaultService() (Service, error) {
-	return NewDuckling()
-}
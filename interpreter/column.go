@@ -62,6 +62,10 @@ type ColumnNode struct {
 	Description string
 	//DateFormat is the format of the data if date type
 	DateFormat string
+	//Aliases are the other words with which the column node can be matched
+	Aliases []string
+	//MatchedAlias is the alias with which the node was matched in the sentence. It is empty if the node was matched with its word
+	MatchedAlias string
 }
 
 type columnNode struct {
@@ -78,6 +82,8 @@ type columnNode struct {
 	DataType      string      `json:"data_type,omitempty"`
 	Description   string      `json:"description"`
 	DateFormat    string      `json:"date_format"`
+	Aliases       []string    `json:"aliases,omitempty"`
+	MatchedAlias  string      `json:"matched_alias,omitempty"`
 }
 
 //Copy will return a copy of the node
@@ -96,6 +102,8 @@ func (c *ColumnNode) Copy() Node {
 		DataType:      c.DataType,
 		Description:   c.Description,
 		DateFormat:    c.DateFormat,
+		Aliases:       c.Aliases,
+		MatchedAlias:  c.MatchedAlias,
 	}
 }
 
@@ -128,12 +136,13 @@ func (c *ColumnNode) Parent() Node {
 func (c *ColumnNode) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&columnNode{
 		c.UID, string(c.Word), c.PUID, c.Name, c.Children, c.Resolved, "Column", c.Dimension, c.Measure, c.AggregationFn, c.DataType, c.Description, c.DateFormat,
+		c.Aliases, c.MatchedAlias,
 	})
 }
 
 //UnmarshalJSON decodes the node from a json
 func (c *ColumnNode) UnmarshalJSON(data []byte) error {
-	m := &ColumnNode{}
+	m := &columnNode{}
 	err := json.Unmarshal(data, m)
 	if err != nil {
 		return err
@@ -150,6 +159,8 @@ func (c *ColumnNode) UnmarshalJSON(data []byte) error {
 	c.DataType = m.DataType
 	c.Description = m.Description
 	c.DateFormat = m.DateFormat
+	c.Aliases = m.Aliases
+	c.MatchedAlias = m.MatchedAlias
 	return nil
 }
 
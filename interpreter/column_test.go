@@ -0,0 +1,34 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package interpreter_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cuttle-ai/octopus/interpreter"
+)
+
+/*
+ * This file contains the tests for the column node
+ */
+
+func TestColumnNodeJSON(t *testing.T) {
+	//decoding the column goes through its json form instead of decoding into the column again
+	c := interpreter.ColumnNode{UID: "sales", Word: []rune("sales"), PUID: "automobile", Name: "sales", Resolved: true, Measure: true,
+		AggregationFn: interpreter.AggregationFnSum, DataType: interpreter.DataTypeFloat, Description: "sales of the cars"}
+	b, err := json.Marshal(&c)
+	if err != nil {
+		t.Fatal("error while encoding the column", err)
+	}
+	decoded := interpreter.ColumnNode{}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal("error while decoding the column", err)
+	}
+	if decoded.UID != c.UID || string(decoded.Word) != string(c.Word) || decoded.PUID != c.PUID || decoded.Name != c.Name ||
+		!decoded.Resolved || !decoded.Measure || decoded.AggregationFn != c.AggregationFn || decoded.DataType != c.DataType || decoded.Description != c.Description {
+		t.Error("Expected the decoded column to be", c, "got", decoded)
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package interpreter
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+ * This file contains the diagnostics reported while interpreting the tokens
+ */
+
+//DiagnosticKind is the kind of a diagnostic
+type DiagnosticKind string
+
+const (
+	//RuleFailed is reported when a rule matched couldn't be applied to the tokens
+	RuleFailed DiagnosticKind = "rule_failed"
+	//UnresolvedToken is reported for a token not used by any of the rules
+	UnresolvedToken DiagnosticKind = "unresolved_token"
+	//FilterDropped is reported for a filter dropped while building the sql query
+	FilterDropped DiagnosticKind = "filter_dropped"
+)
+
+//Span is the span of the tokens to which a diagnostic refers to
+type Span struct {
+	//Start is the position of the first token in the span
+	Start int `json:"start"`
+	//End is the position after the last token in the span
+	End int `json:"end"`
+	//Text is the text of the tokens in the span
+	Text string `json:"text,omitempty"`
+}
+
+//Diagnostic explains why a part of the sentence was ignored while interpreting it
+type Diagnostic struct {
+	//Kind is the kind of the diagnostic
+	Kind DiagnosticKind `json:"kind"`
+	//Message is the human readable message of the diagnostic
+	Message string `json:"message"`
+	//Rule is the name of the rule to which the diagnostic refers to
+	Rule string `json:"rule,omitempty"`
+	//Span is the span of the tokens to which the diagnostic refers to. It is nil if the tokens are not known
+	Span *Span `json:"span,omitempty"`
+	//Filter is the filter dropped
+	Filter *OperatorNode `json:"-"`
+}
+
+//tokenSpan returns the span of the tokens from the start till the end
+func tokenSpan(toks []FastToken, start, end int) *Span {
+	if start < 0 || end > len(toks) || start >= end {
+		return nil
+	}
+	b := strings.Builder{}
+	for _, tok := range toks[start:end] {
+		b.WriteString(string(tok.Word))
+	}
+	return &Span{Start: toks[start].Pos, End: toks[end-1].Pos + 1, Text: strings.TrimSpace(b.String())}
+}
+
+//filterSpan returns the span of the tokens having the operator, column or the operands of the filter.
+//If none of them are found in the tokens, nil is returned
+func filterSpan(toks []FastToken, f OperatorNode) *Span {
+	/*
+	 * We will find the uids of the nodes in the filter
+	 * Then we will find the first and last tokens having any of them
+	 */
+	uids := map[string]bool{}
+	add := func(uid string) {
+		if len(uid) != 0 {
+			uids[uid] = true
+		}
+	}
+	add(f.UID)
+	if f.Column != nil {
+		add(f.Column.UID)
+	}
+	if f.Value != nil {
+		add(f.Value.UID)
+	}
+	if f.ToValue != nil {
+		add(f.ToValue.UID)
+	}
+	if f.Unknown != nil {
+		add(f.Unknown.UID)
+	}
+	if f.ToUnknown != nil {
+		add(f.ToUnknown.UID)
+	}
+	if f.Number != nil {
+		add(f.Number.UID)
+	}
+	if f.ToNumber != nil {
+		add(f.ToNumber.UID)
+	}
+	if f.Time != nil {
+		add(f.Time.UID)
+	}
+	if f.ToTime != nil {
+		add(f.ToTime.UID)
+	}
+	for _, v := range f.Values {
+		add(v.UID)
+	}
+
+	//finding the tokens
+	start, end := -1, -1
+	for i, tok := range toks {
+		if tokenHasUID(tok, uids) {
+			if start < 0 {
+				start = i
+			}
+			end = i + 1
+		}
+	}
+	return tokenSpan(toks, start, end)
+}
+
+//tokenHasUID returns true if any of the nodes in the token has one of the uids
+func tokenHasUID(tok FastToken, uids map[string]bool) bool {
+	for _, n := range tok.Operators {
+		if uids[n.UID] {
+			return true
+		}
+	}
+	for _, n := range tok.Columns {
+		if uids[n.UID] {
+			return true
+		}
+	}
+	for _, n := range tok.Values {
+		if uids[n.UID] {
+			return true
+		}
+	}
+	for _, n := range tok.Unknowns {
+		if uids[n.UID] {
+			return true
+		}
+	}
+	for _, n := range tok.Numbers {
+		if uids[n.UID] {
+			return true
+		}
+	}
+	for _, n := range tok.Times {
+		if uids[n.UID] {
+			return true
+		}
+	}
+	return false
+}
+
+//unresolvedDiagnostics returns the diagnostics of the tokens not resolved by the rules. Tokens having only spaces are skipped
+func unresolvedDiagnostics(toks []FastToken) []Diagnostic {
+	result := []Diagnostic{}
+	for i, tok := range toks {
+		if tok.resolved() || len(strings.TrimSpace(string(tok.Word))) == 0 {
+			continue
+		}
+		span := tokenSpan(toks, i, i+1)
+		result = append(result, Diagnostic{
+			Kind:    UnresolvedToken,
+			Message: fmt.Sprintf("%q was not used in the query", span.Text),
+			Span:    span,
+		})
+	}
+	return result
+}
+
+//filterIssue returns the reason for which the filter is not valid
+func filterIssue(v OperatorNode) string {
+	/*
+	 * We will check whether the filter has a column
+	 * Then we will check whether the operator is supported for the data type of the column
+	 * Then we will check whether the operands are valid for the column
+	 */
+	if v.Column == nil || len(v.Column.Name) == 0 {
+		return "the filter doesn't have a column"
+	}
+	name, dataType := v.Column.Name, v.Column.DataType
+	if v.Operation == InOperator {
+		if len(v.Values) == 0 {
+			return "the in filter on " + name + " doesn't have any values"
+		}
+		for _, vl := range v.Values {
+			if len(vl.Name) == 0 {
+				return "the in filter on " + name + " has a value without name"
+			}
+			if _, ok := getValue(dataType, vl.Name); !ok {
+				return fmt.Sprintf("%q is not a valid %s for %s", vl.Name, dataType, name)
+			}
+		}
+	}
+	if (dataType == DataTypeInt || dataType == DataTypeFloat || dataType == DataTypeDate) &&
+		(v.Operation != EqOperator && v.Operation != NotEqOperator && v.Operation != GreaterOperator && v.Operation != LessOperator &&
+			v.Operation != BetweenOperator && v.Operation != InOperator) {
+		return fmt.Sprintf("the operator %s is not supported for %s of type %s", v.Operation, name, dataType)
+	}
+	if issue := operandIssue(*v.Column, v.Value, v.Unknown, v.Number, v.Time); len(issue) != 0 {
+		return issue
+	}
+	if v.Operation == BetweenOperator {
+		if v.ToValue == nil && v.ToUnknown == nil && v.ToNumber == nil && v.ToTime == nil {
+			return "the range filter on " + name + " doesn't have the upper bound"
+		}
+		if issue := operandIssue(*v.Column, v.ToValue, v.ToUnknown, v.ToNumber, v.ToTime); len(issue) != 0 {
+			return issue
+		}
+	}
+	return "the filter on " + name + " is not valid"
+}
+
+//operandIssue returns the reason for which the operand is not valid for the column. If the operand is valid, empty string is returned
+func operandIssue(column ColumnNode, value *ValueNode, unknown *UnknownNode, number *NumberNode, t *TimeNode) string {
+	switch {
+	case value != nil:
+		if _, ok := getValue(column.DataType, value.Name); !ok || len(value.Name) == 0 {
+			return fmt.Sprintf("%q is not a valid %s for %s", value.Name, column.DataType, column.Name)
+		}
+	case unknown != nil:
+		word := string(unknown.Word)
+		if !unknown.Literal {
+			word = strings.TrimSpace(word)
+		}
+		if _, ok := getValue(column.DataType, word); !ok || (len(word) == 0 && !unknown.Literal) {
+			return fmt.Sprintf("%q is not a valid %s for %s", word, column.DataType, column.Name)
+		}
+	case number != nil:
+		if _, ok := number.Arg(column.DataType); !ok {
+			return fmt.Sprintf("the number %q can't be compared with %s of type %s", strings.TrimSpace(string(number.Word)), column.Name, column.DataType)
+		}
+	case t != nil:
+		if column.DataType != DataTypeDate {
+			return fmt.Sprintf("the time %q can't be compared with %s of type %s", strings.TrimSpace(string(t.Word)), column.Name, column.DataType)
+		}
+		if !t.Value.IsValid() {
+			return fmt.Sprintf("the time %q is not valid", strings.TrimSpace(string(t.Word)))
+		}
+	default:
+		return "the filter on " + column.Name + " doesn't have a value"
+	}
+	return ""
+}
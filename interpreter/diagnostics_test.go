@@ -0,0 +1,126 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package interpreter_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cuttle-ai/octopus/interpreter"
+	"github.com/cuttle-ai/octopus/testutils"
+)
+
+/*
+ * This file contains the tests for the diagnostics of the interpreter
+ */
+
+var diagnosticsTable = interpreter.TableNode{UID: "diagnostics", Name: "diagnostics"}
+var diagnosticsSales = interpreter.ColumnNode{UID: "sales", PUID: diagnosticsTable.UID, Name: "sales", DataType: interpreter.DataTypeInt}
+
+type droppedFilterTest struct {
+	testutils.Test
+	Filter  interpreter.OperatorNode
+	Message string
+}
+
+var droppedFilterTestcases = []droppedFilterTest{
+	{
+		Test:    testutils.Test{Name: "Type mismatch", Description: "values not convertible to the data type of the column are dropped"},
+		Filter:  interpreter.OperatorNode{Operation: interpreter.EqOperator, Column: &diagnosticsSales, Unknown: &interpreter.UnknownNode{Word: []rune("swift")}},
+		Message: `"swift" is not a valid INT for sales`,
+	},
+	{
+		Test:    testutils.Test{Name: "Unsupported operator", Description: "operators not supported for the data type are dropped"},
+		Filter:  interpreter.OperatorNode{Operation: interpreter.LikeOperator, Column: &diagnosticsSales, Unknown: &interpreter.UnknownNode{Word: []rune("10")}},
+		Message: "the operator LIKE is not supported for sales of type INT",
+	},
+	{
+		Test:    testutils.Test{Name: "Missing upper bound", Description: "range filters without the upper bound are dropped"},
+		Filter:  interpreter.OperatorNode{Operation: interpreter.BetweenOperator, Column: &diagnosticsSales, Unknown: &interpreter.UnknownNode{Word: []rune("10")}},
+		Message: "the range filter on sales doesn't have the upper bound",
+	},
+	{
+		Test:    testutils.Test{Name: "Time on number", Description: "times can't be compared with the columns other than dates"},
+		Filter:  interpreter.OperatorNode{Operation: interpreter.EqOperator, Column: &diagnosticsSales, Time: &interpreter.TimeNode{Word: []rune("today")}},
+		Message: `the time "today" can't be compared with sales of type INT`,
+	},
+	{
+		Test:    testutils.Test{Name: "In value without name", Description: "in filters having a value without name are dropped"},
+		Filter:  interpreter.OperatorNode{Operation: interpreter.InOperator, Column: &diagnosticsSales, Values: []interpreter.ValueNode{{Name: "10"}, {UID: "empty"}}},
+		Message: "the in filter on sales has a value without name",
+	},
+}
+
+func TestToSQLDiagnostics(t *testing.T) {
+	for _, v := range droppedFilterTestcases {
+		t.Run(v.Name, func(t *testing.T) {
+			q := interpreter.Query{
+				Select:  []interpreter.ColumnNode{diagnosticsSales},
+				Tables:  map[string]interpreter.TableNode{diagnosticsTable.UID: diagnosticsTable},
+				Filters: []interpreter.OperatorNode{v.Filter},
+			}
+			s, err := q.ToSQL()
+			if err != nil {
+				t.Fatal("error while converting the query to sql", err)
+			}
+			if strings.Contains(s.Query, "WHERE") {
+				t.Error("Expected the filter to be dropped. Got", s.Query)
+			}
+			if len(s.Diagnostics) != 1 || s.Diagnostics[0].Kind != interpreter.FilterDropped || s.Diagnostics[0].Message != v.Message {
+				t.Error("Expected the diagnostic", v.Message, "got", s.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestInterpretDiagnostics(t *testing.T) {
+	interpreter.AddRule(interpreter.Rule{
+		Name:     "Test rule for unknown filters",
+		Template: []interpreter.Type{interpreter.Column, interpreter.Operator, interpreter.Unknown},
+		Resolve: func(qu interpreter.Query, toks []interpreter.FastToken, index int) (interpreter.Query, error) {
+			toks[index].Columns[0].SetResolved(true)
+			toks[index+1].Operators[0].SetResolved(true)
+			toks[index+2].Unknowns[0].SetResolved(true)
+			toks[index+1].Operators[0].Column = &toks[index].Columns[0]
+			toks[index+1].Operators[0].Unknown = &toks[index+2].Unknowns[0]
+			qu.Filters = append(qu.Filters, toks[index+1].Operators[0])
+			qu.Tables[diagnosticsTable.UID] = diagnosticsTable
+			return qu, nil
+		},
+	}, 0, 0, "DIAGNOSTICS_TEST_RULES")
+	interpreter.AddRule(interpreter.Rule{
+		Name:     "Test rule failing for ranks",
+		Template: []interpreter.Type{interpreter.Rank},
+		Resolve: func(qu interpreter.Query, toks []interpreter.FastToken, index int) (interpreter.Query, error) {
+			return qu, errors.New("couldn't find the measure to rank")
+		},
+	}, 0, 1, "DIAGNOSTICS_TEST_RULES")
+
+	toks := []interpreter.FastToken{
+		{Pos: 0, Word: []rune("top"), Ranks: []interpreter.RankNode{{UID: "top", Word: []rune("top"), Descending: true}}},
+		{Pos: 1, Word: []rune("sales"), Columns: []interpreter.ColumnNode{diagnosticsSales}},
+		{Pos: 2, Word: []rune("is"), Operators: []interpreter.OperatorNode{{UID: "is", Word: []rune("is"), Operation: interpreter.EqOperator}}},
+		{Pos: 3, Word: []rune("swift"), Unknowns: []interpreter.UnknownNode{{UID: "U3", Word: []rune("swift")}}},
+		{Pos: 4, Word: []rune(" please"), Unknowns: []interpreter.UnknownNode{{UID: "U4", Word: []rune(" please")}}},
+	}
+	q, err := interpreter.Interpret(toks)
+	if err != nil {
+		t.Fatal("error while interpreting the tokens", err)
+	}
+	kinds := map[interpreter.DiagnosticKind][]interpreter.Diagnostic{}
+	for _, d := range q.Diagnostics {
+		kinds[d.Kind] = append(kinds[d.Kind], d)
+	}
+	if d := kinds[interpreter.RuleFailed]; len(d) != 1 || d[0].Rule != "Test rule failing for ranks" || d[0].Span == nil || d[0].Span.Start != 0 || d[0].Span.End != 1 {
+		t.Error("Expected the failure of the rank rule on the first token. Got", d)
+	}
+	if d := kinds[interpreter.UnresolvedToken]; len(d) != 2 || d[0].Span.Text != "top" || d[1].Span.Text != "please" {
+		t.Error("Expected the rank and please to be unresolved. Got", d)
+	}
+	if d := kinds[interpreter.FilterDropped]; len(d) != 1 || d[0].Span == nil || d[0].Span.Start != 1 || d[0].Span.End != 4 {
+		t.Error("Expected the filter on sales to be dropped with the span of its tokens. Got", d)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package interpreter
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+ * This file contains the defnition of sql dialects supported while converting a query to sql
+ */
+
+//Dialect controls the dialect specific parts of the sql generated for a query
+type Dialect interface {
+	//Name of the dialect
+	Name() string
+	//QuoteIdentifier quotes the given identifier like a table or column name
+	QuoteIdentifier(name string) string
+	//Placeholder returns the placeholder for the argument at the given position. Position starts from 1
+	Placeholder(pos int) string
+	//Like returns the case insensitive pattern match expression for the given column and placeholder
+	Like(column, placeholder string) string
+	//DateValue converts the time to the argument to be bound for a date column
+	DateValue(t time.Time) interface{}
+	//AggregationFn returns the name of the given aggregation function in the dialect
+	AggregationFn(fn string) string
+}
+
+//DefaultDialect is the dialect used by ToSQL
+var DefaultDialect Dialect = PostgresDialect{}
+
+//PostgresDialect is the dialect for PostgreSQL
+type PostgresDialect struct{}
+
+//Name returns the name of the dialect
+func (p PostgresDialect) Name() string {
+	return "postgres"
+}
+
+//QuoteIdentifier quotes the identifier with double quotes
+func (p PostgresDialect) QuoteIdentifier(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+//Placeholder returns the placeholder as $n
+func (p PostgresDialect) Placeholder(pos int) string {
+	return "$" + strconv.Itoa(pos)
+}
+
+//Like returns the pattern match expression with ILIKE
+func (p PostgresDialect) Like(column, placeholder string) string {
+	return column + " ILIKE " + placeholder
+}
+
+//DateValue returns the time as it is
+func (p PostgresDialect) DateValue(t time.Time) interface{} {
+	return t
+}
+
+//AggregationFn returns the aggregation function as it is
+func (p PostgresDialect) AggregationFn(fn string) string {
+	return fn
+}
+
+//MySQLDialect is the dialect for MySQL
+type MySQLDialect struct{}
+
+//Name returns the name of the dialect
+func (m MySQLDialect) Name() string {
+	return "mysql"
+}
+
+//QuoteIdentifier quotes the identifier with backticks
+func (m MySQLDialect) QuoteIdentifier(name string) string {
+	return "`" + strings.Replace(name, "`", "``", -1) + "`"
+}
+
+//Placeholder returns the placeholder as ?
+func (m MySQLDialect) Placeholder(pos int) string {
+	return "?"
+}
+
+//Like returns the pattern match expression with LIKE. MySQL's default collations are case insensitive
+func (m MySQLDialect) Like(column, placeholder string) string {
+	return column + " LIKE " + placeholder
+}
+
+//DateValue returns the time as it is
+func (m MySQLDialect) DateValue(t time.Time) interface{} {
+	return t
+}
+
+//AggregationFn returns the aggregation function as it is
+func (m MySQLDialect) AggregationFn(fn string) string {
+	return fn
+}
+
+//SQLiteDateFormat is the format in which dates are bound for SQLite
+const SQLiteDateFormat = "2006-01-02 15:04:05"
+
+//SQLiteDialect is the dialect for SQLite
+type SQLiteDialect struct{}
+
+//Name returns the name of the dialect
+func (s SQLiteDialect) Name() string {
+	return "sqlite"
+}
+
+//QuoteIdentifier quotes the identifier with double quotes
+func (s SQLiteDialect) QuoteIdentifier(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+//Placeholder returns the placeholder as ?
+func (s SQLiteDialect) Placeholder(pos int) string {
+	return "?"
+}
+
+//Like returns the pattern match expression with LIKE. SQLite's LIKE is case insensitive for ascii characters
+func (s SQLiteDialect) Like(column, placeholder string) string {
+	return column + " LIKE " + placeholder
+}
+
+//DateValue returns the time formatted as text since SQLite doesn't have a date type
+func (s SQLiteDialect) DateValue(t time.Time) interface{} {
+	return t.Format(SQLiteDateFormat)
+}
+
+//AggregationFn returns the aggregation function as it is
+func (s SQLiteDialect) AggregationFn(fn string) string {
+	return fn
+}
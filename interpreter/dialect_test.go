@@ -0,0 +1,149 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package interpreter_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cuttle-ai/octopus/datetime"
+	"github.com/cuttle-ai/octopus/interpreter"
+)
+
+/*
+ * This file contains the golden tests for the sql dialects
+ */
+
+type dialectGolden struct {
+	s    string
+	args []interface{}
+}
+
+type dialectQuery struct {
+	q       interpreter.Query
+	n       string
+	goldens map[string]dialectGolden
+}
+
+var testColumnOrderDate = interpreter.ColumnNode{Name: "order date", DataType: interpreter.DataTypeDate}
+
+var testColumnSales = interpreter.ColumnNode{Name: "sales", DataType: interpreter.DataTypeInt, Measure: true}
+
+var testTime = time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+var testTimeNode = interpreter.TimeNode{
+	Value: datetime.Value{
+		Type: "interval",
+		From: &datetime.TimeValue{Value: testTime.Format(time.RFC3339), Time: &testTime},
+	},
+}
+
+var testDialects = []interpreter.Dialect{
+	interpreter.PostgresDialect{},
+	interpreter.MySQLDialect{},
+	interpreter.SQLiteDialect{},
+}
+
+var testDialectQueries = []dialectQuery{
+	{
+		q: interpreter.Query{
+			Select:  []interpreter.ColumnNode{testColumnSales},
+			GroupBy: []interpreter.ColumnNode{testColumnCity},
+			Tables:  map[string]interpreter.TableNode{"1": testTable},
+		},
+		n: "group by",
+		goldens: map[string]dialectGolden{
+			"postgres": {s: `SELECT COUNT("sales") AS "sales", "city" FROM "stores" GROUP BY "city"`, args: []interface{}{}},
+			"mysql":    {s: "SELECT COUNT(`sales`) AS `sales`, `city` FROM `stores` GROUP BY `city`", args: []interface{}{}},
+			"sqlite":   {s: `SELECT COUNT("sales") AS "sales", "city" FROM "stores" GROUP BY "city"`, args: []interface{}{}},
+		},
+	},
+	{
+		q: interpreter.Query{
+			Select: []interpreter.ColumnNode{testColumnCity},
+			Tables: map[string]interpreter.TableNode{"1": testTable},
+			Filters: []interpreter.OperatorNode{
+				{Operation: interpreter.ContainsOperator, Column: &testColumnCity, Unknown: &testUnknownCity},
+				{Operation: interpreter.GreaterOperator, Column: &testColumnOrderDate, Time: &testTimeNode},
+			},
+		},
+		n: "like and date filters",
+		goldens: map[string]dialectGolden{
+			"postgres": {
+				s:    `SELECT "city" FROM "stores" WHERE "city" ILIKE $1 AND "order date" >= $2`,
+				args: []interface{}{"%Delhi%", testTime},
+			},
+			"mysql": {
+				s:    "SELECT `city` FROM `stores` WHERE `city` LIKE ? AND `order date` >= ?",
+				args: []interface{}{"%Delhi%", testTime},
+			},
+			"sqlite": {
+				s:    `SELECT "city" FROM "stores" WHERE "city" LIKE ? AND "order date" >= ?`,
+				args: []interface{}{"%Delhi%", "2019-01-01 00:00:00"},
+			},
+		},
+	},
+	{
+		q: interpreter.Query{
+			Select: []interpreter.ColumnNode{testColumnCity},
+			Tables: map[string]interpreter.TableNode{"1": testTable},
+			Filters: []interpreter.OperatorNode{
+				{
+					Operation: interpreter.BetweenOperator,
+					Column:    &testColumnOrderDate,
+					Unknown:   &interpreter.UnknownNode{Word: []rune("2019-01-01")},
+					ToUnknown: &interpreter.UnknownNode{Word: []rune("2019-03-31")},
+				},
+				{
+					Operation: interpreter.BetweenOperator,
+					Column:    &interpreter.ColumnNode{Name: "price", DataType: interpreter.DataTypeFloat},
+					Unknown:   &interpreter.UnknownNode{Word: []rune("10")},
+					ToUnknown: &interpreter.UnknownNode{Word: []rune("20.5")},
+				},
+			},
+		},
+		n: "between filters",
+		goldens: map[string]dialectGolden{
+			"postgres": {
+				s:    `SELECT "city" FROM "stores" WHERE "order date" BETWEEN $1 AND $2 AND "price" BETWEEN $3 AND $4`,
+				args: []interface{}{testTime, time.Date(2019, time.March, 31, 0, 0, 0, 0, time.UTC), 10.0, 20.5},
+			},
+			"mysql": {
+				s:    "SELECT `city` FROM `stores` WHERE `order date` BETWEEN ? AND ? AND `price` BETWEEN ? AND ?",
+				args: []interface{}{testTime, time.Date(2019, time.March, 31, 0, 0, 0, 0, time.UTC), 10.0, 20.5},
+			},
+			"sqlite": {
+				s:    `SELECT "city" FROM "stores" WHERE "order date" BETWEEN ? AND ? AND "price" BETWEEN ? AND ?`,
+				args: []interface{}{"2019-01-01 00:00:00", "2019-03-31 00:00:00", 10.0, 20.5},
+			},
+		},
+	},
+}
+
+func TestToSQLWithDialect(t *testing.T) {
+	for _, v := range testDialectQueries {
+		for _, d := range testDialects {
+			t.Run(v.n+" "+d.Name(), func(t *testing.T) {
+				golden, ok := v.goldens[d.Name()]
+				if !ok {
+					t.Fatal("couldn't find the golden query for the dialect", d.Name())
+				}
+				s, err := v.q.ToSQLWithDialect(d)
+				if err != nil {
+					t.Error("error while running the test", err)
+					return
+				}
+				if strings.Compare(s.Query, golden.s) != 0 {
+					t.Error("Expected query", "`"+golden.s+"`", "got", "`"+s.Query+"`")
+				}
+				if !reflect.DeepEqual(s.Args, golden.args) {
+					t.Error("Expected args", golden.args, "got", s.Args)
+				}
+			})
+		}
+	}
+}
@@ -5,6 +5,7 @@
 package interpreter
 
 import (
+	"context"
 	"sync"
 	"time"
 )
@@ -60,11 +61,13 @@ type DICTRequest struct {
 	ID string
 	//Type is the type of the dictionary request. It can have Add, Get, Remove
 	Type DICTRequestType
-	//DICT is the dictionary under watch
+	//DICT is the dictionary under watch. The dictionary sent back for the get requests is shared with the tokenizer
+	//and must not be mutated. Use Copy to get a mutable copy
 	DICT DICT
 	//Valid indicates that the dict is valid. During get requests, if valid is false then cache couldn't find the dict
 	Valid bool
-	//Out channel for sending response to the requester
+	//Out channel for sending response to the requester. If the requester can give up waiting, it should be buffered.
+	//It is optional for add requests. If given, the request is sent back with Valid false if the dictionary couldn't be added
 	Out chan DICTRequest
 }
 
@@ -92,15 +95,17 @@ func SetDefaultDICTAggregator(agg DICTAggregator) {
 }
 
 func getDICT(ID string, update bool) (DICT, bool) {
+	//the aggregator is called outside the lock so that a slow aggregator doesn't block the other ids
 	defaultAggregator.m.Lock()
-	if defaultAggregator.agg == nil {
+	agg := defaultAggregator.agg
+	defaultAggregator.m.Unlock()
+	if agg == nil {
 		return DICT{}, false
 	}
-	d, err := defaultAggregator.agg.Get(ID, update)
+	d, err := agg.Get(ID, update)
 	if err != nil {
 		return DICT{}, false
 	}
-	defaultAggregator.m.Unlock()
 	return d, true
 }
 
@@ -108,7 +113,7 @@ func init() {
 	DICTInputChannel = make(chan DICTRequest)
 	defaultAggregator = aggregator{}
 	go Dictionary(DICTInputChannel)
-	go cacheClearCheck(DICTInputChannel)
+	go cacheClearCheck()
 }
 
 //SendDICTToChannel sends a dict request to the channel. This function is to be used with go routines so that
@@ -117,109 +122,61 @@ func SendDICTToChannel(ch chan DICTRequest, req DICTRequest) {
 	ch <- req
 }
 
-//Dictionary is the cache for providing the dictionary to the platform on demand
+//Dictionary serves the requests to the dictionary store coming through the channel.
+//The dictionaries are kept in a concurrent store, so the requests requiring a load from the aggregator
+//are served concurrently without blocking the other requests.
+//The add requests are still served in order, so building the tokenizer of a large dictionary delays the requests behind it.
+//The dictionaries sent back for the get requests are shared with the tokenizer and must not be mutated.
+//AddDICT can be used to add a dictionary without going through the channel
 func Dictionary(in chan DICTRequest) {
 	/*
 	 * We will go into an infinte loop
 	 * Will wait for the requests to come through the channel
-	 * Based on the type of the request we will remove them from memory
+	 * Requests updating the store are served in order
+	 * Requests which may have to load from the aggregator are served concurrently
 	 */
-	dict := make(map[string]DICT)
 	for {
 		req := <-in
 		switch req.Type {
 		case DICTAdd:
 			req.DICT.LastUsed = time.Now()
-			dict[req.ID] = req.DICT
-			go SendTokenizerToChannel(
-				TokenizerInputChannel,
-				Request{
-					ID:        req.ID,
-					Type:      TokenizerAdd,
-					Out:       make(chan Request),
-					Tokenizer: Tokenizer{Map: req.DICT.Map},
-				})
-			break
-		case DICTGet:
-			req.DICT, req.Valid = dict[req.ID]
-			if !req.Valid {
-				req.DICT, req.Valid = getDICT(req.ID, false)
+			err := dictionaries.add(req.ID, req.DICT)
+			if req.Out != nil {
+				req.Valid = err == nil
+				go SendDICTToChannel(req.Out, req)
 			}
-			//we will only give the copies of the dict item to avoid mutation
-			req.DICT = req.DICT.Copy()
-			req.DICT.LastUsed = time.Now()
-			dict[req.ID] = req.DICT
-			go SendDICTToChannel(req.Out, req)
 			break
-		case DICTPreCache:
-			req.DICT, req.Valid = dict[req.ID]
-			if !req.Valid {
-				req.DICT, req.Valid = getDICT(req.ID, false)
-			}
-			if !req.Valid {
+		case DICTGet, DICTPreCache:
+			if snap, ok := dictionaries.get(req.ID); ok {
+				req.DICT, req.Valid = snap.DICT(), true
+				go SendDICTToChannel(req.Out, req)
 				break
 			}
-			req.DICT.LastUsed = time.Now()
-			dict[req.ID] = req.DICT
-			SendTokenizerToChannel(
-				TokenizerInputChannel,
-				Request{
-					ID:        req.ID,
-					Type:      TokenizerAdd,
-					Out:       make(chan Request),
-					Tokenizer: Tokenizer{Map: req.DICT.Map},
-				})
-			go SendDICTToChannel(req.Out, req)
+			go func(req DICTRequest) {
+				snap, ok, _ := dictionaries.fetch(context.Background(), req.ID, false)
+				if ok {
+					req.DICT = snap.DICT()
+				}
+				req.Valid = ok
+				SendDICTToChannel(req.Out, req)
+			}(req)
 			break
 		case DICTRemoveCheck:
-			//we will iterate over the cache and check the last usage
-			t := time.Now()
-			for k, v := range dict {
-				if v.LastUsed.Add(DICTExpiry).After(t) {
-					continue
-				}
-				delete(dict, k)
-				go SendTokenizerToChannel(
-					TokenizerInputChannel,
-					Request{
-						ID:   k,
-						Type: TokenizerRemove,
-					})
-			}
+			dictionaries.removeExpired(DICTExpiry)
 			break
 		case DICTRemove:
-			delete(dict, req.ID)
-			go SendTokenizerToChannel(
-				TokenizerInputChannel,
-				Request{
-					ID:   req.ID,
-					Type: TokenizerRemove,
-				})
+			dictionaries.remove(req.ID)
 			break
 		case DICTUpdate:
-			delete(dict, req.ID)
-			d, ok := getDICT(req.ID, true)
-			if !ok {
-				break
-			}
-			req.DICT.LastUsed = time.Now()
-			dict[req.ID] = d
-			SendTokenizerToChannel(
-				TokenizerInputChannel,
-				Request{
-					ID:        req.ID,
-					Type:      TokenizerAdd,
-					Out:       make(chan Request),
-					Tokenizer: Tokenizer{Map: d.Map},
-				})
+			go dictionaries.fetch(context.Background(), req.ID, true)
 			break
 		}
 	}
 }
 
-func cacheClearCheck(in chan DICTRequest) {
+func cacheClearCheck() {
 	for {
 		time.Sleep(DICTClearCheckInterval)
-		go SendDICTToChannel(in, DICTRequest{Type: DICTRemoveCheck})
+		dictionaries.removeExpired(DICTExpiry)
 	}
 }
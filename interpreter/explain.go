@@ -0,0 +1,229 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package interpreter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+)
+
+/*
+ * This file contains the explain mode of the interpreter which traces the rules applied on the tokens
+ */
+
+//Explanation is the best interpretation of the tokens along with the trace of the rules applied to get it
+type Explanation struct {
+	//Query is the query interpreted
+	Query Query `json:"query"`
+	//Score is the score of the interpretation
+	Score float64 `json:"score"`
+	//Tokens are the tokens of the path in the lattice interpreted with the node chosen for each of them
+	Tokens []TraceToken `json:"tokens"`
+	//Steps has the trace of each rule matched in the order in which they were run
+	Steps []TraceStep `json:"steps"`
+}
+
+//TraceToken is a token in the trace with the node chosen for it
+type TraceToken struct {
+	//Pos is the position of the token
+	Pos int `json:"pos"`
+	//Word is the word of the token
+	Word string `json:"word"`
+	//Type is the type of the node in the token
+	Type string `json:"type,omitempty"`
+	//UID is the uid of the node in the token
+	UID string `json:"uid,omitempty"`
+}
+
+//TraceStep is the trace of a rule matched with the tokens
+type TraceStep struct {
+	//Rule is the name of the rule
+	Rule string `json:"rule"`
+	//Template is the template of the rule
+	Template []string `json:"template"`
+	//Matches are the indices of the tokens at which the template of the rule matched
+	Matches []int `json:"matches"`
+	//Applications has the trace of the rule applied at each of the matches
+	Applications []TraceApplication `json:"applications"`
+}
+
+//TraceApplication is the trace of a rule applied at a match
+type TraceApplication struct {
+	//Index is the index of the token at which the rule was applied
+	Index int `json:"index"`
+	//Tokens are the tokens matched by the template of the rule
+	Tokens []TraceToken `json:"tokens"`
+	//Resolved are the tokens whose nodes were marked resolved by the rule
+	Resolved []TraceToken `json:"resolved,omitempty"`
+	//Error is the error returned by the rule
+	Error string `json:"error,omitempty"`
+	//Changes are the changes made by the rule to the query
+	Changes []QueryChange `json:"changes,omitempty"`
+}
+
+//QueryChange is the change in a field of the query
+type QueryChange struct {
+	//Field is the json name of the field in the query
+	Field string `json:"field"`
+	//Before is the value of the field before the change. It is empty if the field was not set
+	Before json.RawMessage `json:"before,omitempty"`
+	//After is the value of the field after the change. It is empty if the field was unset
+	After json.RawMessage `json:"after,omitempty"`
+}
+
+//typeNames has the names of the node types
+var typeNames = map[Type]string{
+	KnowledgeBase: "KnowledgeBase",
+	Table:         "Table",
+	Column:        "Column",
+	Value:         "Value",
+	Operator:      "Operator",
+	GroupBy:       "GroupBy",
+	AggregationFn: "AggregationFn",
+	Unknown:       "Unknown",
+	Ignore:        "Ignore",
+	Context:       "Context",
+	Time:          "Time",
+	Rank:          "Rank",
+	Number:        "Number",
+}
+
+//InterpretExplain interprets the given list of tokens and explains the rules applied to get the best interpretation
+func InterpretExplain(toks []FastToken) (*Explanation, error) {
+	return InterpretExplainContext(context.Background(), toks)
+}
+
+//InterpretExplainContext interprets the given list of tokens and explains the rules applied to get the best interpretation.
+//The explanation has the trace of each rule matched with the path of the best interpretation in the order in which the rules were run.
+//If the context is done before the rules are applied, ErrTimeout or the error of the context is returned
+func InterpretExplainContext(ctx context.Context, toks []FastToken) (*Explanation, error) {
+	cs, err := interpretCandidates(ctx, toks, true)
+	if err != nil {
+		return nil, err
+	}
+	best := cs[0]
+	result := &Explanation{Query: best.Query, Score: best.Score, Tokens: []TraceToken{}, Steps: best.steps}
+	for _, tok := range best.path {
+		result.Tokens = append(result.Tokens, traceToken(tok))
+	}
+	if result.Steps == nil {
+		result.Steps = []TraceStep{}
+	}
+	return result, nil
+}
+
+//traceToken returns the trace of the token of a path
+func traceToken(tok FastToken) TraceToken {
+	result := TraceToken{Pos: tok.Pos, Word: string(tok.Word)}
+	if cs := tokenChoices(tok); len(cs) > 0 {
+		result.Type = typeNames[cs[0].t]
+		result.UID = tok.nodeUID(cs[0])
+	}
+	return result
+}
+
+//nodeUID returns the uid of the chosen node in the token
+func (f FastToken) nodeUID(c choice) string {
+	switch c.t {
+	case Operator:
+		return f.Operators[c.i].UID
+	case Value:
+		return f.Values[c.i].UID
+	case Time:
+		return f.Times[c.i].UID
+	case Rank:
+		return f.Ranks[c.i].UID
+	case Number:
+		return f.Numbers[c.i].UID
+	case Column:
+		return f.Columns[c.i].UID
+	case Table:
+		return f.Tables[c.i].UID
+	case Unknown:
+		return f.Unknowns[c.i].UID
+	}
+	return ""
+}
+
+//newTraceStep returns the trace step of the rule
+func newTraceStep(rule Rule) *TraceStep {
+	result := &TraceStep{Rule: rule.Name, Template: []string{}, Matches: append([]int{}, rule.Matches...), Applications: []TraceApplication{}}
+	for _, t := range rule.Template {
+		result.Template = append(result.Template, typeNames[t])
+	}
+	return result
+}
+
+//traceApplication has the state of the query and the tokens before a rule was applied
+type traceApplication struct {
+	//TraceApplication is the trace being recorded
+	TraceApplication
+	//resolved has the resolved state of the tokens before the rule was applied
+	resolved []bool
+	//before has the fields of the query before the rule was applied
+	before map[string]json.RawMessage
+}
+
+//startApplication records the state of the query and the tokens before the rule is applied at the index
+func startApplication(q Query, toks []FastToken, rule Rule, index int) *traceApplication {
+	result := &traceApplication{TraceApplication: TraceApplication{Index: index, Tokens: []TraceToken{}}, before: queryFields(q)}
+	for i := index; i < index+len(rule.Template) && i < len(toks); i++ {
+		result.Tokens = append(result.Tokens, traceToken(toks[i]))
+	}
+	for _, tok := range toks {
+		result.resolved = append(result.resolved, tok.resolved())
+	}
+	return result
+}
+
+//finish returns the trace of the application with the tokens resolved and the changes made to the query by the rule
+func (t *traceApplication) finish(q Query, toks []FastToken, err error) TraceApplication {
+	result := t.TraceApplication
+	if err != nil {
+		result.Error = err.Error()
+	}
+	for i, tok := range toks {
+		if i < len(t.resolved) && !t.resolved[i] && tok.resolved() {
+			result.Resolved = append(result.Resolved, traceToken(tok))
+		}
+	}
+	result.Changes = queryChanges(t.before, queryFields(q))
+	return result
+}
+
+//queryFields returns the json encoding of the fields of the query mapped to their json names.
+//Fields which are not set are not present
+func queryFields(q Query) map[string]json.RawMessage {
+	result := map[string]json.RawMessage{}
+	b, err := json.Marshal(q)
+	if err != nil {
+		return result
+	}
+	json.Unmarshal(b, &result)
+	return result
+}
+
+//queryChanges returns the changes between the fields of the query sorted by their name
+func queryChanges(before, after map[string]json.RawMessage) []QueryChange {
+	fields := []string{}
+	for k := range before {
+		fields = append(fields, k)
+	}
+	for k := range after {
+		if _, ok := before[k]; !ok {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+	result := []QueryChange{}
+	for _, f := range fields {
+		if !bytes.Equal(before[f], after[f]) {
+			result = append(result, QueryChange{Field: f, Before: before[f], After: after[f]})
+		}
+	}
+	return result
+}
@@ -0,0 +1,283 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package interpreter
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+/*
+ * This file contains the utilities for matching the misspelled words in the sentence with the dictionary
+ */
+
+//FuzzyConfig is the configuration of the fuzzy matching of the unknown words against the dictionary
+type FuzzyConfig struct {
+	//Enabled if true, the unknown words are matched against the dictionary words within the allowed edit distance
+	Enabled bool
+	//MaxDistance is the maximum edit distance allowed. A word is allowed one edit for every four runes in it upto the max distance
+	MaxDistance int
+	//MinLength is the minimum no. of runes a word should have to be fuzzy matched. Short words are left unknown as they are close to too many words
+	MinLength int
+}
+
+//DefaultFuzzyConfig is the default configuration of the fuzzy matching. Fuzzy matching is disabled by default
+var DefaultFuzzyConfig = FuzzyConfig{MaxDistance: 2, MinLength: 4}
+
+//FuzzyMatch has the details of the dictionary word matched with a misspelled word in the sentence
+type FuzzyMatch struct {
+	//Word is the normalised word or alias in the dictionary that was matched
+	Word string
+	//Distance is the edit distance between the word in the sentence and the dictionary word. A transposition is a single edit
+	Distance int
+	//Confidence is the confidence of the match from 0 to 1. A match with lesser edits relative to the length of the words has higher confidence
+	Confidence float64
+}
+
+//bkNode is a node in the bk tree
+type bkNode struct {
+	word     []rune
+	children map[int]*bkNode
+}
+
+//bkTree is a bk tree of the words indexed by their levenshtein distance
+type bkTree struct {
+	root *bkNode
+}
+
+//add adds the word to the tree
+func (b *bkTree) add(word []rune) {
+	if b.root == nil {
+		b.root = &bkNode{word: word, children: map[int]*bkNode{}}
+		return
+	}
+	n := b.root
+	for {
+		d := levenshtein(n.word, word)
+		if d == 0 {
+			return
+		}
+		child, ok := n.children[d]
+		if !ok {
+			n.children[d] = &bkNode{word: word, children: map[int]*bkNode{}}
+			return
+		}
+		n = child
+	}
+}
+
+//search returns the words within the given levenshtein distance from the word
+func (b *bkTree) search(word []rune, distance int) [][]rune {
+	result := [][]rune{}
+	if b.root == nil {
+		return result
+	}
+	stack := []*bkNode{b.root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		d := levenshtein(n.word, word)
+		if d <= distance {
+			result = append(result, n.word)
+		}
+		for k, child := range n.children {
+			if k >= d-distance && k <= d+distance {
+				stack = append(stack, child)
+			}
+		}
+	}
+	return result
+}
+
+//fuzzyIndex is the index of the single word entries in the dictionary for fuzzy matching.
+//It is built lazily on the first fuzzy match as it is not required when fuzzy matching is disabled
+type fuzzyIndex struct {
+	once sync.Once
+	tree bkTree
+}
+
+//build builds the index from the normalised words and aliases in the dictionary
+func (f *fuzzyIndex) build(words map[string]Token) {
+	f.once.Do(func() {
+		for word := range words {
+			if len(word) == 0 || strings.IndexFunc(word, unicode.IsSpace) >= 0 {
+				continue
+			}
+			f.tree.add([]rune(word))
+		}
+	})
+}
+
+//closest returns the closest word in the index within the given edit distance
+func (f *fuzzyIndex) closest(word []rune, distance int) (string, int, bool) {
+	/*
+	 * A transposition is two edits in levenshtein distance
+	 * So we will search the tree with double the distance and check the edit distance with transpositions
+	 * Ties are broken by the lexical order of the words so that the match is deterministic
+	 */
+	best, bestDistance, found := "", distance+1, false
+	for _, candidate := range f.tree.search(word, 2*distance) {
+		d := editDistance(word, candidate)
+		if d < bestDistance || (d == bestDistance && string(candidate) < best) {
+			best, bestDistance, found = string(candidate), d, d <= distance
+		}
+	}
+	return best, bestDistance, found
+}
+
+//FuzzyMatch will match the unknown words in the tokens against the words and the aliases in the dictionary of the tokenizer.
+//The words are matched in their normalised form like the exact matches.
+//The unknown tokens having a match are split into the tokens of the unknown words and the matched word
+func (t Tokenizer) FuzzyMatch(toks []Token, config FuzzyConfig) []Token {
+	/*
+	 * We will build the fuzzy index from the normalised words if not built already
+	 * Then we will iterate through the tokens having unknowns
+	 * Each word in the unknown token is matched against the index
+	 * Matched words become tokens with the nodes of the dictionary word and the words in between remain unknowns
+	 */
+	if t.fuzzy == nil {
+		return toks
+	}
+	words := t.words
+	if words == nil {
+		words = t.Map
+	}
+	t.fuzzy.build(words)
+	result := []Token{}
+	for _, tok := range toks {
+		if len(tok.Nodes) != 1 || tok.Nodes[0].Type() != Unknown {
+			result = append(result, tok)
+			continue
+		}
+		if u, ok := tok.Nodes[0].(*UnknownNode); ok && u.Literal {
+			//quoted literals are not matched
+			result = append(result, tok)
+			continue
+		}
+		result = append(result, t.fuzzyMatchToken(tok, words, config)...)
+	}
+	return result
+}
+
+//fuzzyMatchToken returns the tokens of the unknown token after matching its words against the normalised words
+func (t Tokenizer) fuzzyMatchToken(tok Token, words map[string]Token, config FuzzyConfig) []Token {
+	result := []Token{}
+	uid := tok.Nodes[0].ID()
+	start := 0
+	//unknown adds the unknown word from the start till the end if it is not just spaces
+	unknown := func(end int) {
+		w := tok.Word[start:end]
+		if len(strings.TrimSpace(string(w))) == 0 {
+			return
+		}
+		result = append(result, Token{Word: w, Nodes: []Node{&UnknownNode{UID: fmt.Sprint(uid, "-", len(result)), Word: w}}})
+	}
+
+	for i := 0; i < len(tok.Word); {
+		//finding the word
+		if unicode.IsSpace(tok.Word[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(tok.Word) && !unicode.IsSpace(tok.Word[j]) {
+			j++
+		}
+		word := tok.Word[i:j]
+		norm := []rune(normaliseWord(string(word), t.Normaliser))
+
+		//matching the word
+		distance := len(norm) / 4
+		if distance > config.MaxDistance {
+			distance = config.MaxDistance
+		}
+		if len(word) < config.MinLength || distance == 0 {
+			i = j
+			continue
+		}
+		match, d, ok := t.fuzzy.closest(norm, distance)
+		dTok, dOk := words[match]
+		if !ok || !dOk {
+			i = j
+			continue
+		}
+		unknown(i)
+		longest := len(norm)
+		if l := len([]rune(match)); l > longest {
+			longest = l
+		}
+		result = append(result, Token{
+			Word:           word,
+			Nodes:          dTok.Nodes,
+			MatchedAliases: dTok.MatchedAliases,
+			Fuzzy:          &FuzzyMatch{Word: match, Distance: d, Confidence: 1 - float64(d)/float64(longest)},
+		})
+		//the space following the match is skipped like the tokens matched exactly
+		start, i = j, j
+		if start < len(tok.Word) && unicode.IsSpace(tok.Word[start]) {
+			start++
+		}
+	}
+	unknown(len(tok.Word))
+	return result
+}
+
+//levenshtein returns the levenshtein distance between the words
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+//editDistance returns the edit distance between the words where the transposition of adjacent runes is a single edit
+func editDistance(a, b []rune) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min3(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && d[i-2][j-2]+1 < d[i][j] {
+				d[i][j] = d[i-2][j-2] + 1
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
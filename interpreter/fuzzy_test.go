@@ -0,0 +1,127 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package interpreter_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cuttle-ai/octopus/interpreter"
+	"github.com/cuttle-ai/octopus/testutils"
+)
+
+/*
+ * This file contains the tests for the fuzzy matching of the misspelled words
+ */
+
+//fuzzyResult is the expected result of a token
+type fuzzyResult struct {
+	Word       string
+	Unknown    bool
+	Match      string
+	Confidence float64
+}
+
+type fuzzyTest struct {
+	testutils.Test
+	Input    string
+	Enabled  bool
+	Expected []fuzzyResult
+}
+
+var fuzzyTestcases = []fuzzyTest{
+	{
+		Test:    testutils.Test{Name: "Transpositions", Description: "transposed runes in the column and the value names are matched with their normalised words"},
+		Input:   "slaes of Swfit cars",
+		Enabled: true,
+		Expected: []fuzzyResult{
+			{Word: "slaes", Match: "sale", Confidence: 0.75},
+			{Word: "of ", Unknown: true},
+			{Word: "Swfit", Match: "swift", Confidence: 0.8},
+			{Word: "cars"},
+		},
+	},
+	{
+		Test:    testutils.Test{Name: "Substitution", Description: "a substituted rune is matched"},
+		Input:   "brsnd",
+		Enabled: true,
+		Expected: []fuzzyResult{
+			{Word: "brsnd", Match: "brand", Confidence: 0.8},
+		},
+	},
+	{
+		Test:    testutils.Test{Name: "Aliases", Description: "misspelled aliases are matched"},
+		Input:   "turnvoer",
+		Enabled: true,
+		Expected: []fuzzyResult{
+			{Word: "turnvoer", Match: "turnover", Confidence: 0.875},
+		},
+	},
+	{
+		Test:    testutils.Test{Name: "Short words", Description: "short words are not fuzzy matched"},
+		Input:   "cra",
+		Enabled: true,
+		Expected: []fuzzyResult{
+			{Word: "cra", Unknown: true},
+		},
+	},
+	{
+		Test:    testutils.Test{Name: "Too many edits", Description: "words needing more edits than allowed are left unknown"},
+		Input:   "sleas",
+		Enabled: true,
+		Expected: []fuzzyResult{
+			{Word: "sleas", Unknown: true},
+		},
+	},
+	{
+		Test:  testutils.Test{Name: "Disabled", Description: "misspelled words are unknowns when fuzzy matching is disabled"},
+		Input: "slaes",
+		Expected: []fuzzyResult{
+			{Word: "slaes", Unknown: true},
+		},
+	},
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	d := tenantDICT("fuzzy-user")
+	d.Map["sales"] = interpreter.Token{Word: []rune("sales"), Nodes: []interpreter.Node{&interpreter.ColumnNode{UID: "fuzzy-user-sales", Name: "sales", Aliases: []string{"turnover"}}}}
+	//words in the dictionary need not be lower cased
+	d.Map["Swift"] = d.Map["swift"]
+	delete(d.Map, "swift")
+	if err := interpreter.AddDICT("fuzzy-user", d); err != nil {
+		t.Fatal("error while adding the dictionary", err)
+	}
+	defer interpreter.SetTokenizerConfig(interpreter.DefaultTokenizerConfig)
+	for _, v := range fuzzyTestcases {
+		t.Run(v.Name, func(t *testing.T) {
+			config := interpreter.DefaultTokenizerConfig
+			config.Fuzzy.Enabled = v.Enabled
+			interpreter.SetTokenizerConfig(config)
+			toks, err := interpreter.TokenizeContext(context.Background(), "fuzzy-user", []rune(v.Input))
+			if err != nil {
+				t.Fatal("error while tokenizing the sentence", err)
+			}
+			if len(toks) != len(v.Expected) {
+				t.Fatal("Expected", len(v.Expected), "tokens. Got", toks)
+			}
+			for i, e := range v.Expected {
+				tok := toks[i]
+				if string(tok.Word) != e.Word || (len(tok.Unknowns) != 0) != e.Unknown {
+					t.Error("Expected", e, "at", i, "got", tok)
+					continue
+				}
+				if len(e.Match) == 0 {
+					if tok.Fuzzy != nil {
+						t.Error("Expected no fuzzy match for", e.Word, "got", tok.Fuzzy)
+					}
+					continue
+				}
+				if tok.Fuzzy == nil || tok.Fuzzy.Word != e.Match || tok.Fuzzy.Confidence != e.Confidence {
+					t.Error("Expected the fuzzy match", e.Match, "with confidence", e.Confidence, "got", tok.Fuzzy)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package interpreter
+
+/*
+ * This file contains the routing of the filters on the measures to having filters of a grouped query
+ */
+
+//RouteMeasureFilters moves the filters on the measures to the having filters if the query is grouped by a dimension.
+//The filter groups are moved to the having groups if all the filters in them are on the measures.
+//The rules borrow the select field from the group by when there is nothing else to select.
+//So a query selecting only dimensions without aggregation is considered to be grouped by them when it has filters on the measures
+func (q *Query) RouteMeasureFilters() {
+	/*
+	 * We will find the filters and the filter groups on the measures
+	 * If there are none, there is nothing to do
+	 * If the query doesn't have group by, the dimensions borrowed to the select are moved back to the group by
+	 * Then we will move the filters on the measures to having with the aggregation fn of the measure
+	 */
+	measures := []OperatorNode{}
+	filters := []OperatorNode{}
+	for _, f := range q.Filters {
+		if f.Column != nil && f.Column.Measure {
+			measures = append(measures, f)
+		} else {
+			filters = append(filters, f)
+		}
+	}
+	grouped := []OperatorNode{}
+	measureGroups := []FilterGroup{}
+	groups := []FilterGroup{}
+	for _, g := range q.FilterGroups {
+		if fs, ok := measureGroupFilters(g); ok {
+			grouped = append(grouped, fs...)
+			measureGroups = append(measureGroups, g)
+		} else {
+			groups = append(groups, g)
+		}
+	}
+	if len(measures) == 0 && len(measureGroups) == 0 {
+		return
+	}
+
+	//finding the grouping
+	if len(q.GroupBy) == 0 {
+		if !selectsOnlyDimensions(q.Select, append(append([]OperatorNode{}, measures...), grouped...)) {
+			return
+		}
+		q.GroupBy = q.Select
+		q.Select = nil
+	}
+
+	//moving the filters to having
+	for _, f := range measures {
+		q.Having = append(q.Having, aggregateFilter(*q, f))
+	}
+	for _, g := range measureGroups {
+		q.HavingGroups = append(q.HavingGroups, aggregateGroup(*q, g))
+	}
+	q.Filters = filters
+	if len(measureGroups) != 0 {
+		q.FilterGroups = groups
+	}
+}
+
+//measureGroupFilters returns the filters in the group and its sub groups.
+//If any of the filters is not on a measure or the group doesn't have filters, it will return false
+func measureGroupFilters(g FilterGroup) ([]OperatorNode, bool) {
+	result := []OperatorNode{}
+	for _, f := range g.Filters {
+		if f.Column == nil || !f.Column.Measure {
+			return nil, false
+		}
+		result = append(result, f)
+	}
+	for _, sg := range g.Groups {
+		fs, ok := measureGroupFilters(sg)
+		if !ok {
+			return nil, false
+		}
+		result = append(result, fs...)
+	}
+	return result, len(result) != 0
+}
+
+//aggregateFilter returns the filter with a copy of its column having the aggregation fn of the measure
+func aggregateFilter(q Query, f OperatorNode) OperatorNode {
+	column := f.Column.Copy().(*ColumnNode)
+	column.AggregationFn = measureAggregationFn(q, *column)
+	f.Column = column
+	return f
+}
+
+//aggregateGroup returns the copy of the group with the filters in it and its sub groups aggregated
+func aggregateGroup(q Query, g FilterGroup) FilterGroup {
+	result := FilterGroup{Logic: g.Logic}
+	for _, f := range g.Filters {
+		result.Filters = append(result.Filters, aggregateFilter(q, f))
+	}
+	for _, sg := range g.Groups {
+		result.Groups = append(result.Groups, aggregateGroup(q, sg))
+	}
+	return result
+}
+
+//selectsOnlyDimensions returns true if the columns selected are dimensions without any aggregation
+//which are not filtered as measures
+func selectsOnlyDimensions(selected []ColumnNode, measures []OperatorNode) bool {
+	if len(selected) == 0 {
+		return false
+	}
+	for _, s := range selected {
+		if !s.Dimension || len(s.AggregationFn) != 0 {
+			return false
+		}
+		for _, m := range measures {
+			if m.Column.UID == s.UID && m.Column.PUID == s.PUID {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+//measureAggregationFn returns the aggregation function to be applied on the measure.
+//The aggregation fn of the measure is borrowed from the select field if the measure is being selected
+func measureAggregationFn(q Query, column ColumnNode) string {
+	for _, s := range q.Select {
+		if s.UID == column.UID && s.PUID == column.PUID && len(s.AggregationFn) != 0 {
+			return s.AggregationFn
+		}
+	}
+	if len(column.AggregationFn) != 0 {
+		return column.AggregationFn
+	}
+	if column.DataType == DataTypeFloat || column.DataType == DataTypeInt {
+		return AggregationFnSum
+	}
+	return AggregationFnCount
+}
@@ -5,7 +5,11 @@
 //Package interpreter has the utilities and defnition of the interpreter implementation of octopus
 package interpreter
 
-import "fmt"
+import (
+	"context"
+	"encoding/json"
+	"sort"
+)
 
 /*
  * This file contains the defnition of interpreter of octopus
@@ -13,24 +17,186 @@ import "fmt"
 
 //Interpret the given list of token to meaningful query
 func Interpret(toks []FastToken) (*Query, error) {
+	return InterpretContext(context.Background(), toks)
+}
+
+//InterpretContext interprets the given list of token to meaningful query.
+//The best ranked among the interpretations of the alternative nodes of the tokens is returned.
+//If the context is done before the rules are applied, ErrTimeout or the error of the context is returned
+func InterpretContext(ctx context.Context, toks []FastToken) (*Query, error) {
+	qs, err := InterpretAllContext(ctx, toks)
+	if err != nil {
+		return nil, err
+	}
+	return &qs[0], nil
+}
+
+//InterpretAll returns the distinct interpretations of the given list of tokens ranked by their score
+func InterpretAll(toks []FastToken) ([]Query, error) {
+	return InterpretAllContext(context.Background(), toks)
+}
+
+//InterpretAllContext returns the distinct interpretations of the paths in the lattice of the tokens ranked by their score.
+//Atleast one interpretation is returned if there is no error.
+//If the context is done before the rules are applied, ErrTimeout or the error of the context is returned
+func InterpretAllContext(ctx context.Context, toks []FastToken) ([]Query, error) {
+	ins, err := InterpretNContext(ctx, toks, 0)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Query, len(ins))
+	for i, in := range ins {
+		result[i] = in.Query
+	}
+	return result, nil
+}
+
+//InterpretN returns upto n distinct interpretations of the given list of tokens along with their scores ranked by the score
+func InterpretN(toks []FastToken, n int) ([]Interpretation, error) {
+	return InterpretNContext(context.Background(), toks, n)
+}
+
+//InterpretNContext returns upto n distinct interpretations of the paths in the lattice of the tokens along with their scores.
+//The interpretations are ranked by their score. Among the interpretations with the same score, the one from the more preferred path comes first.
+//If n is not positive, all the distinct interpretations are returned. Atleast one interpretation is returned if there is no error.
+//If the context is done before the rules are applied, ErrTimeout or the error of the context is returned
+func InterpretNContext(ctx context.Context, toks []FastToken, n int) ([]Interpretation, error) {
+	cs, err := interpretCandidates(ctx, toks, false)
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 && len(cs) > n {
+		cs = cs[:n]
+	}
+	result := make([]Interpretation, len(cs))
+	for i, c := range cs {
+		result[i] = c.Interpretation
+	}
+	return result, nil
+}
+
+//candidate is an interpretation of a path in the lattice
+type candidate struct {
+	Interpretation
+	//path has the tokens of the path
+	path []FastToken
+	//steps has the trace of the rules applied on the path if explained
+	steps []TraceStep
+}
+
+//interpretCandidates returns the distinct interpretations of the paths in the lattice of the tokens ranked by their score.
+//If explain is true, the trace of the rules applied on each path is recorded
+func interpretCandidates(ctx context.Context, toks []FastToken, explain bool) ([]candidate, error) {
+	/*
+	 * We will interpret each path in the lattice of the tokens and score it
+	 * If the interpretation is same as that of a previous path, the one with the better score is kept
+	 * Then the interpretations are ranked by their score
+	 */
+	result := []candidate{}
+	seen := map[string]int{}
+	for _, path := range LatticePaths(toks, MaxLatticePaths) {
+		res, err := interpretPath(ctx, path, explain)
+		if err != nil {
+			return nil, err
+		}
+		c := candidate{Interpretation: Interpretation{Query: *res.query, Score: score(path, res.applied, DefaultScoreWeights)}, path: path, steps: res.steps}
+
+		//skipping the duplicates
+		//diagnostics are not part of the interpretation while finding the duplicates
+		c.Query.Diagnostics = res.diagnostics
+		if b, err := json.Marshal(res.query); err == nil {
+			if i, ok := seen[string(b)]; ok {
+				if c.Score > result[i].Score {
+					result[i] = c
+				}
+				continue
+			}
+			seen[string(b)] = len(result)
+		}
+		result = append(result, c)
+	}
+
+	//ranking the interpretations
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Score > result[j].Score })
+	return result, nil
+}
+
+//pathResult is the result of interpreting a path in the lattice
+type pathResult struct {
+	//query is the query interpreted
+	query *Query
+	//applied has the rules which resolved atleast a token
+	applied []Rule
+	//diagnostics of the query
+	diagnostics []Diagnostic
+	//steps has the trace of the rules applied if explained
+	steps []TraceStep
+}
+
+//interpretPath runs the rules on the tokens of a path in the lattice to get the query.
+//The rules which resolved atleast a token and the diagnostics of the query are returned along with it.
+//If explain is true, the trace of each rule matched is also returned
+func interpretPath(ctx context.Context, toks []FastToken, explain bool) (pathResult, error) {
 	/*
 	 * Will run the tokens through the rule match to get the rules to be run
 	 * Then will run the rules on the tokens
+	 * Before applying each rule we will check whether the context is done
+	 * Then we will route the filters on the measures to having since the grouping of the query is known only after the rules
+	 * Then we will find the diagnostics of the unresolved tokens and the filters dropped from the sql query
 	 */
 	//running through rules for finding matches
 	rules := MatchRules(toks)
 
 	//iterating through the rules to resolve them
 	q := &Query{Tables: map[string]TableNode{}}
+	result := pathResult{query: q, applied: []Rule{}, diagnostics: []Diagnostic{}}
 	for _, rule := range rules {
+		resolved := resolvedTokens(toks)
+		var step *TraceStep
+		if explain {
+			step = newTraceStep(rule)
+		}
 		for _, i := range rule.Matches {
+			if ctx.Err() != nil {
+				return pathResult{}, contextError(ctx)
+			}
+			var app *traceApplication
+			if explain {
+				app = startApplication(*q, toks, rule, i)
+			}
 			qu, err := rule.Resolve(*q, toks, i)
 			if err != nil {
-				fmt.Println("Couldn't apply the rule", rule.Name, "to the query at index", i, err)
+				result.diagnostics = append(result.diagnostics, Diagnostic{
+					Kind:    RuleFailed,
+					Message: "couldn't apply the rule " + rule.Name + ". " + err.Error(),
+					Rule:    rule.Name,
+					Span:    tokenSpan(toks, i, i+len(rule.Template)),
+				})
+				if explain {
+					step.Applications = append(step.Applications, app.finish(*q, toks, err))
+				}
 				continue
 			}
 			*q = qu
+			if explain {
+				step.Applications = append(step.Applications, app.finish(*q, toks, nil))
+			}
+		}
+		if resolvedTokens(toks) > resolved {
+			result.applied = append(result.applied, rule)
+		}
+		if explain {
+			result.steps = append(result.steps, *step)
+		}
+	}
+
+	//finding the diagnostics
+	result.diagnostics = append(result.diagnostics, unresolvedDiagnostics(toks)...)
+	if s, err := q.ToSQL(); err == nil {
+		for _, d := range s.Diagnostics {
+			d.Span = filterSpan(toks, *d.Filter)
+			result.diagnostics = append(result.diagnostics, d)
 		}
 	}
-	return q, nil
+	return result, nil
 }
@@ -5,6 +5,7 @@
 package interpreter_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -31,3 +32,24 @@ func TestInterpret(t *testing.T) {
 
 	fmt.Println(qu)
 }
+
+func TestInterpretContext(t *testing.T) {
+	interpreter.AddRule(interpreter.Rule{
+		Name:     "Test rule for columns",
+		Template: []interpreter.Type{interpreter.Column},
+		Resolve: func(qu interpreter.Query, toks []interpreter.FastToken, index int) (interpreter.Query, error) {
+			return qu, nil
+		},
+	}, 0, 0, "TEST_RULES")
+	toks := []interpreter.FastToken{{Pos: 0, Word: []rune("cars"), Columns: []interpreter.ColumnNode{{UID: "cars", Word: []rune("cars")}}}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	time.Sleep(time.Millisecond)
+	if _, err := interpreter.InterpretContext(ctx, toks); err != interpreter.ErrTimeout {
+		t.Error("Expected the timeout error. Got", err)
+	}
+	if _, err := interpreter.InterpretContext(context.Background(), toks); err != nil {
+		t.Error("Expected the tokens to be interpreted. Got", err)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package interpreter
+
+import (
+	"fmt"
+	"sort"
+)
+
+/*
+ * This file contains the utilities for inferring the join path between the tables in a query
+ */
+
+//Join is the join of a table with a table preceding it in the join path
+type Join struct {
+	//Table is the table being joined
+	Table TableNode
+	//Column is the name of the column in the table being joined
+	Column string
+	//RefTable is the table already in the join path to which the table is joined
+	RefTable TableNode
+	//RefColumn is the name of the column in the ref table
+	RefColumn string
+}
+
+//relation is an undirected relationship between two tables found from the foreign keys
+type relation struct {
+	fromUID    string
+	fromColumn string
+	toUID      string
+	toColumn   string
+}
+
+//normalize orders the relation so that the same relationship declared from both the tables are equal
+func (r relation) normalize() relation {
+	if r.fromUID < r.toUID || (r.fromUID == r.toUID && r.fromColumn <= r.toColumn) {
+		return r
+	}
+	return relation{fromUID: r.toUID, fromColumn: r.toColumn, toUID: r.fromUID, toColumn: r.fromColumn}
+}
+
+//JoinPath will find the path with which the tables in the query can be joined.
+//It returns the table to start the join from and the joins to be made in order.
+//If the tables can be joined in more than one way or can't be joined at all, an error is returned
+func (q Query) JoinPath() (TableNode, []Join, error) {
+	/*
+	 * We will collect the relationships between the tables in the query from their foreign keys
+	 * If two tables have more than one relationship, the path is ambiguous
+	 * Then we will choose the root table as the one with most foreign keys
+	 * Then we will do a breadth first walk from the root to get the joins
+	 * If any table is not reachable there is no path
+	 * If there are more relationships than required for a tree, the tables form a cycle and the path is ambiguous
+	 */
+	if len(q.Tables) == 0 {
+		return TableNode{}, nil, fmt.Errorf("couldn't find any tables")
+	}
+
+	//collecting the relationships
+	uids := []string{}
+	for k := range q.Tables {
+		uids = append(uids, k)
+	}
+	sort.Strings(uids)
+	pairs := map[[2]string][]relation{}
+	outgoing := map[string]int{}
+	for _, uid := range uids {
+		for _, fk := range q.Tables[uid].ForeignKeys {
+			if _, ok := q.Tables[fk.RefTableUID]; !ok || fk.RefTableUID == uid {
+				continue
+			}
+			outgoing[uid]++
+			r := relation{fromUID: uid, fromColumn: fk.Column, toUID: fk.RefTableUID, toColumn: fk.RefColumn}.normalize()
+			key := [2]string{r.fromUID, r.toUID}
+			exists := false
+			for _, e := range pairs[key] {
+				if e == r {
+					exists = true
+					break
+				}
+			}
+			if !exists {
+				pairs[key] = append(pairs[key], r)
+			}
+		}
+	}
+
+	//checking for the ambiguous relationships between two tables
+	//the pairs are checked in order so that the same pair is reported across the runs
+	keys := make([][2]string, 0, len(pairs))
+	for key := range pairs {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	adjacency := map[string][]relation{}
+	for _, key := range keys {
+		rs := pairs[key]
+		if len(rs) > 1 {
+			return TableNode{}, nil, fmt.Errorf("ambiguous join path between the tables %s and %s. Found %d relationships between them",
+				q.Tables[key[0]].Name, q.Tables[key[1]].Name, len(rs))
+		}
+		adjacency[key[0]] = append(adjacency[key[0]], rs[0])
+		adjacency[key[1]] = append(adjacency[key[1]], rs[0])
+	}
+
+	//choosing the root table
+	root := uids[0]
+	for _, uid := range uids {
+		if outgoing[uid] > outgoing[root] {
+			root = uid
+		}
+	}
+
+	//walking through the relationships from the root
+	joins := []Join{}
+	visited := map[string]bool{root: true}
+	queue := []string{root}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		rs := adjacency[current]
+		sort.Slice(rs, func(i, j int) bool {
+			return other(rs[i], current) < other(rs[j], current)
+		})
+		for _, r := range rs {
+			next := other(r, current)
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+			queue = append(queue, next)
+			j := Join{Table: q.Tables[next], RefTable: q.Tables[current]}
+			if r.fromUID == next {
+				j.Column, j.RefColumn = r.fromColumn, r.toColumn
+			} else {
+				j.Column, j.RefColumn = r.toColumn, r.fromColumn
+			}
+			joins = append(joins, j)
+		}
+	}
+
+	//checking whether all the tables are reachable
+	for _, uid := range uids {
+		if !visited[uid] {
+			return TableNode{}, nil, fmt.Errorf("couldn't find a join path between the tables %s and %s",
+				q.Tables[root].Name, q.Tables[uid].Name)
+		}
+	}
+
+	//if there are more relationships than the joins, the tables form a cycle
+	if len(pairs) > len(joins) {
+		return TableNode{}, nil, fmt.Errorf("ambiguous join path as the tables in the query can be joined in more than one way")
+	}
+
+	return q.Tables[root], joins, nil
+}
+
+//other returns the uid of the table at the other end of the relation
+func other(r relation, uid string) string {
+	if r.fromUID == uid {
+		return r.toUID
+	}
+	return r.fromUID
+}
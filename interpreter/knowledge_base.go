@@ -4,7 +4,10 @@
 
 package interpreter
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 /*
  * This file contains the definition of knowledge base type node
@@ -36,29 +39,34 @@ type KnowledgeBaseNode struct {
 	Description string
 	//KBType indicates the type of the knowledgebase
 	KBType KnowledgeBaseType
+	//FiscalYearStart is the month in which the fiscal year starts for the tables of the knowledge base.
+	//A table can override it with its own. If not set, the fiscal year is same as the calendar year
+	FiscalYearStart time.Month
 }
 
 type knowledgebaseNode struct {
-	UID         string            `json:"uid,omitempty"`
-	Word        string            `json:"word,omitempty"`
-	Name        string            `json:"name,omitempty"`
-	Children    []Node            `json:"children,omitempty"`
-	Resolved    bool              `json:"resolved,omitempty"`
-	Type        string            `json:"type,omitempty"`
-	Description string            `json:"description"`
-	KBType      KnowledgeBaseType `json:"kb_type"`
+	UID             string            `json:"uid,omitempty"`
+	Word            string            `json:"word,omitempty"`
+	Name            string            `json:"name,omitempty"`
+	Children        []Node            `json:"children,omitempty"`
+	Resolved        bool              `json:"resolved,omitempty"`
+	Type            string            `json:"type,omitempty"`
+	Description     string            `json:"description"`
+	KBType          KnowledgeBaseType `json:"kb_type"`
+	FiscalYearStart time.Month        `json:"fiscal_year_start,omitempty"`
 }
 
 //Copy will return a copy of the node
 func (k *KnowledgeBaseNode) Copy() Node {
 	return &KnowledgeBaseNode{
-		UID:         k.UID,
-		Word:        k.Word,
-		Name:        k.Name,
-		Children:    k.Children,
-		Resolved:    k.Resolved,
-		Description: k.Description,
-		KBType:      k.KBType,
+		UID:             k.UID,
+		Word:            k.Word,
+		Name:            k.Name,
+		Children:        k.Children,
+		Resolved:        k.Resolved,
+		Description:     k.Description,
+		KBType:          k.KBType,
+		FiscalYearStart: k.FiscalYearStart,
 	}
 }
 
@@ -90,7 +98,7 @@ func (k *KnowledgeBaseNode) Parent() Node {
 //MarshalJSON encodes the node into a serializable json
 func (k *KnowledgeBaseNode) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&knowledgebaseNode{
-		k.UID, string(k.Word), k.Name, k.Children, k.Resolved, "KnowledgeBase", k.Description, k.KBType,
+		k.UID, string(k.Word), k.Name, k.Children, k.Resolved, "KnowledgeBase", k.Description, k.KBType, k.FiscalYearStart,
 	})
 }
 
@@ -108,6 +116,7 @@ func (k *KnowledgeBaseNode) UnmarshalJSON(data []byte) error {
 	k.Resolved = m.Resolved
 	k.Description = m.Description
 	k.KBType = m.KBType
+	k.FiscalYearStart = m.FiscalYearStart
 	return nil
 }
 
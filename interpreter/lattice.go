@@ -0,0 +1,205 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package interpreter
+
+import (
+	"container/heap"
+	"strconv"
+	"strings"
+)
+
+/*
+ * This file contains the lattice of the alternative node assignments of the tokens
+ */
+
+//MaxLatticePaths is the maximum no. of paths in the lattice of the tokens that are interpreted
+var MaxLatticePaths = 32
+
+//choice is a node of a token chosen for a path in the lattice
+type choice struct {
+	//t is the type of the node
+	t Type
+	//i is the index of the node in the nodes of its type in the token
+	i int
+}
+
+//tokenChoices returns the nodes of the token that can be chosen in the order of their preference.
+//The node types are preferred in the same order as the pattern of the tokens
+func tokenChoices(tok FastToken) []choice {
+	result := []choice{}
+	add := func(t Type, n int) {
+		for i := 0; i < n; i++ {
+			result = append(result, choice{t, i})
+		}
+	}
+	add(Operator, len(tok.Operators))
+	add(Value, len(tok.Values))
+	add(Time, len(tok.Times))
+	add(Rank, len(tok.Ranks))
+	add(Number, len(tok.Numbers))
+	add(Column, len(tok.Columns))
+	add(Table, len(tok.Tables))
+	add(Unknown, len(tok.Unknowns))
+	return result
+}
+
+//pick returns a copy of the token having only the chosen node
+func (f FastToken) pick(c choice) FastToken {
+	result := FastToken{Pos: f.Pos, Word: f.Word, Discarded: f.Discarded, Fuzzy: f.Fuzzy}
+	switch c.t {
+	case Operator:
+		result.Operators = []OperatorNode{f.Operators[c.i]}
+	case Value:
+		result.Values = []ValueNode{f.Values[c.i]}
+	case Time:
+		result.Times = []TimeNode{f.Times[c.i]}
+	case Rank:
+		result.Ranks = []RankNode{f.Ranks[c.i]}
+	case Number:
+		result.Numbers = []NumberNode{f.Numbers[c.i]}
+	case Column:
+		result.Columns = []ColumnNode{f.Columns[c.i]}
+	case Table:
+		result.Tables = []TableNode{f.Tables[c.i]}
+	case Unknown:
+		result.Unknowns = []UnknownNode{f.Unknowns[c.i]}
+	}
+	return result
+}
+
+//resolved returns true if any node in the token is resolved
+func (f FastToken) resolved() bool {
+	for _, n := range f.Operators {
+		if n.IsResolved() {
+			return true
+		}
+	}
+	for _, n := range f.Values {
+		if n.IsResolved() {
+			return true
+		}
+	}
+	for _, n := range f.Times {
+		if n.IsResolved() {
+			return true
+		}
+	}
+	for _, n := range f.Ranks {
+		if n.IsResolved() {
+			return true
+		}
+	}
+	for _, n := range f.Numbers {
+		if n.IsResolved() {
+			return true
+		}
+	}
+	for _, n := range f.Columns {
+		if n.IsResolved() {
+			return true
+		}
+	}
+	for _, n := range f.Tables {
+		if n.IsResolved() {
+			return true
+		}
+	}
+	for _, n := range f.Unknowns {
+		if n.IsResolved() {
+			return true
+		}
+	}
+	return false
+}
+
+//latticePath is a path in the lattice with the index of the choice made for each token
+type latticePath struct {
+	choices []int
+	cost    int
+}
+
+//key returns the unique key of the path
+func (l latticePath) key() string {
+	b := strings.Builder{}
+	for _, c := range l.choices {
+		b.WriteString(strconv.Itoa(c))
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+//latticeQueue is the priority queue of the paths ordered by their cost
+type latticeQueue []latticePath
+
+func (q latticeQueue) Len() int      { return len(q) }
+func (q latticeQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q latticeQueue) Less(i, j int) bool {
+	if q[i].cost != q[j].cost {
+		return q[i].cost < q[j].cost
+	}
+	for k := range q[i].choices {
+		if q[i].choices[k] != q[j].choices[k] {
+			return q[i].choices[k] < q[j].choices[k]
+		}
+	}
+	return false
+}
+func (q *latticeQueue) Push(x interface{}) { *q = append(*q, x.(latticePath)) }
+func (q *latticeQueue) Pop() interface{} {
+	old := *q
+	x := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return x
+}
+
+//LatticePaths returns the paths in the lattice of the alternative nodes of the tokens. Each path has the tokens with a single node chosen for each of them.
+//Paths are returned in the order of their preference upto the given max. The cost of a path is the sum of the preference of the nodes chosen.
+//So the first path has the most preferred node of each token and the paths differing from it by lesser preferred nodes follow.
+//If max is not positive, MaxLatticePaths is used
+func LatticePaths(toks []FastToken, max int) [][]FastToken {
+	/*
+	 * We will find the choices of each token
+	 * Then we will enumerate the paths in the order of their cost starting from the path with the first choice of each token
+	 * The next paths of a path are formed by choosing the next choice of one of its tokens
+	 */
+	if max <= 0 {
+		max = MaxLatticePaths
+	}
+	choices := make([][]choice, len(toks))
+	for i, tok := range toks {
+		choices[i] = tokenChoices(tok)
+	}
+
+	//enumerating the paths
+	result := [][]FastToken{}
+	q := &latticeQueue{{choices: make([]int, len(toks))}}
+	seen := map[string]bool{(*q)[0].key(): true}
+	for q.Len() > 0 && len(result) < max {
+		p := heap.Pop(q).(latticePath)
+		path := make([]FastToken, len(toks))
+		for i, tok := range toks {
+			if len(choices[i]) == 0 {
+				path[i] = tok
+				continue
+			}
+			path[i] = tok.pick(choices[i][p.choices[i]])
+		}
+		result = append(result, path)
+
+		//adding the next paths
+		for i := range toks {
+			if p.choices[i]+1 >= len(choices[i]) {
+				continue
+			}
+			next := latticePath{choices: append([]int{}, p.choices...), cost: p.cost + 1}
+			next.choices[i]++
+			if k := next.key(); !seen[k] {
+				seen[k] = true
+				heap.Push(q, next)
+			}
+		}
+	}
+	return result
+}
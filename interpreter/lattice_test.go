@@ -0,0 +1,159 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/cuttle-ai/octopus/interpreter"
+)
+
+/*
+ * This file contains the tests for the lattice of the tokens
+ */
+
+var latticeTable = &interpreter.TableNode{UID: "lattice", Name: "lattice"}
+var latticeOtherTable = &interpreter.TableNode{UID: "lattice-other", Name: "lattice other"}
+var latticeCars = interpreter.ColumnNode{UID: "cars", PUID: latticeTable.UID, PN: latticeTable, Name: "cars", DataType: interpreter.DataTypeString}
+var latticeSwiftColumn = interpreter.ColumnNode{UID: "swift-column", PUID: latticeTable.UID, PN: latticeTable, Name: "swift", DataType: interpreter.DataTypeString}
+var latticeSwiftValue = interpreter.ValueNode{UID: "swift-value", PUID: latticeCars.UID, PN: &latticeCars, Name: "Swift"}
+
+//latticeTokens returns the tokens of cars swift where swift is both a column and a value
+func latticeTokens() []interpreter.FastToken {
+	return []interpreter.FastToken{
+		{Pos: 0, Word: []rune("cars"), Columns: []interpreter.ColumnNode{latticeCars}},
+		{Pos: 1, Word: []rune("swift"), Columns: []interpreter.ColumnNode{latticeSwiftColumn}, Values: []interpreter.ValueNode{latticeSwiftValue}},
+	}
+}
+
+//loadLatticeRules loads the rules for the lattice tests.
+//Columns followed by their values are filters and the rest of the columns are selected
+func loadLatticeRules() {
+	interpreter.AddRule(interpreter.Rule{
+		Name:     "Test rule for column values",
+		Template: []interpreter.Type{interpreter.Column, interpreter.Value},
+		Resolve: func(qu interpreter.Query, toks []interpreter.FastToken, index int) (interpreter.Query, error) {
+			if toks[index].Columns[0].IsResolved() || toks[index].Columns[0].UID != toks[index+1].Values[0].PUID {
+				return qu, nil
+			}
+			toks[index].Columns[0].SetResolved(true)
+			toks[index+1].Values[0].SetResolved(true)
+			qu.Filters = append(qu.Filters, interpreter.OperatorNode{Operation: interpreter.EqOperator, Column: &toks[index].Columns[0], Value: &toks[index+1].Values[0]})
+			return qu, nil
+		},
+	}, 0, 0, "LATTICE_TEST_RULES")
+	interpreter.AddRule(interpreter.Rule{
+		Name:     "Test rule for columns",
+		Template: []interpreter.Type{interpreter.Column},
+		Resolve: func(qu interpreter.Query, toks []interpreter.FastToken, index int) (interpreter.Query, error) {
+			if toks[index].Columns[0].IsResolved() {
+				return qu, nil
+			}
+			toks[index].Columns[0].SetResolved(true)
+			qu.Select = append(qu.Select, toks[index].Columns[0])
+			return qu, nil
+		},
+	}, 0, 1, "LATTICE_TEST_RULES")
+}
+
+func TestLatticePaths(t *testing.T) {
+	toks := latticeTokens()
+	toks = append(toks, interpreter.FastToken{Pos: 2, Word: []rune("sales"), Tables: []interpreter.TableNode{*latticeTable, *latticeOtherTable}})
+	paths := interpreter.LatticePaths(toks, 0)
+	if len(paths) != 4 {
+		t.Fatal("Expected 4 paths in the lattice. Got", len(paths))
+	}
+	if len(paths[0][1].Values) != 1 || len(paths[0][1].Columns) != 0 || paths[0][2].Tables[0].UID != latticeTable.UID {
+		t.Error("Expected the first path to have the most preferred node of each token. Got", paths[0])
+	}
+	if paths[3][1].Columns[0].UID != latticeSwiftColumn.UID || paths[3][2].Tables[0].UID != latticeOtherTable.UID {
+		t.Error("Expected the last path to have the least preferred node of each token. Got", paths[3])
+	}
+	if paths = interpreter.LatticePaths(toks, 2); len(paths) != 2 {
+		t.Error("Expected the paths to be limited to 2. Got", len(paths))
+	}
+	for _, path := range interpreter.LatticePaths(toks, 0) {
+		for _, tok := range path {
+			if n := len(tok.Columns) + len(tok.Values) + len(tok.Tables); n != 1 {
+				t.Error("Expected each token in the path to have a single node. Got", n, "in", tok)
+			}
+		}
+	}
+}
+
+func TestInterpretAll(t *testing.T) {
+	loadLatticeRules()
+	toks := latticeTokens()
+	qs, err := interpreter.InterpretAll(toks)
+	if err != nil {
+		t.Fatal("error while interpreting the tokens", err)
+	}
+	if len(qs) != 2 {
+		t.Fatal("Expected 2 interpretations of swift as a value and a column. Got", len(qs))
+	}
+	if len(qs[0].Filters) != 1 || qs[0].Filters[0].Value.UID != latticeSwiftValue.UID {
+		t.Error("Expected the first interpretation to filter by the value swift. Got", qs[0])
+	}
+	if len(qs[1].Select) != 2 || qs[1].Select[1].UID != latticeSwiftColumn.UID {
+		t.Error("Expected the second interpretation to select the column swift. Got", qs[1])
+	}
+	if toks[1].Values[0].IsResolved() || toks[1].Columns[0].IsResolved() {
+		t.Error("Expected the tokens passed not to be mutated")
+	}
+
+	//paths with the same interpretation are not repeated
+	toks = append(toks, interpreter.FastToken{Pos: 2, Word: []rune("sales"), Tables: []interpreter.TableNode{*latticeTable, *latticeOtherTable}})
+	if qs, err = interpreter.InterpretAll(toks); err != nil || len(qs) != 2 {
+		t.Error("Expected the interpretations to be distinct. Got", len(qs), err)
+	}
+	for _, r := range interpreter.MatchRules(interpreter.LatticePaths(toks, 1)[0]) {
+		if len(r.Template) == 0 {
+			t.Error("Expected the template of the matched rule", r.Name, "to be copied")
+		}
+	}
+	q, err := interpreter.Interpret(toks)
+	if err != nil || len(q.Filters) != 1 {
+		t.Error("Expected the best interpretation to be returned. Got", q, err)
+	}
+}
+
+func TestInterpretN(t *testing.T) {
+	loadLatticeRules()
+	toks := latticeTokens()
+	toks = append(toks, interpreter.FastToken{Pos: 2, Word: []rune(" of brand"), Unknowns: []interpreter.UnknownNode{{UID: "U2", Word: []rune(" of brand")}}})
+	ins, err := interpreter.InterpretN(toks, 1)
+	if err != nil {
+		t.Fatal("error while interpreting the tokens", err)
+	}
+	if len(ins) != 1 || len(ins[0].Query.Filters) != 1 {
+		t.Fatal("Expected the best interpretation only. Got", ins)
+	}
+	if ins, err = interpreter.InterpretN(toks, 0); err != nil || len(ins) != 2 {
+		t.Fatal("Expected all the interpretations. Got", ins, err)
+	}
+	if ins[0].Score <= ins[1].Score || ins[0].Score <= 0 || ins[0].Score >= 1 {
+		t.Error("Expected the interpretations to be ranked by the score between 0 and 1. Got", ins[0].Score, ins[1].Score)
+	}
+
+	//fuzzy matched tokens are penalised
+	fuzzy := latticeTokens()
+	fuzzy[1].Fuzzy = &interpreter.FuzzyMatch{Word: "swift", Distance: 1, Confidence: 0.8}
+	fIns, err := interpreter.InterpretN(fuzzy, 1)
+	if err != nil || len(fIns) != 1 {
+		t.Fatal("error while interpreting the tokens", err)
+	}
+	exact, err := interpreter.InterpretN(latticeTokens(), 1)
+	if err != nil || len(exact) != 1 {
+		t.Fatal("error while interpreting the tokens", err)
+	}
+	if fIns[0].Score >= exact[0].Score {
+		t.Error("Expected the fuzzy match to have lesser score. Got", fIns[0].Score, "and", exact[0].Score)
+	}
+
+	//unresolved unknowns lower the score
+	if exact[0].Score <= ins[0].Score {
+		t.Error("Expected the unknowns to lower the score. Got", ins[0].Score, "and", exact[0].Score)
+	}
+}
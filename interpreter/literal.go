@@ -0,0 +1,109 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package interpreter
+
+import (
+	"sort"
+	"strconv"
+)
+
+/*
+ * This file contains the utilities for recognising the quoted literals in a sentence
+ */
+
+//quotePairs has the closing quote of each opening quote recognised for the literals
+var quotePairs = map[rune]rune{'"': '"', '\'': '\'', '“': '”', '‘': '’'}
+
+//literal is a quoted span in the sentence
+type literal struct {
+	//start is the position of the opening quote
+	start int
+	//end is the position after the closing quote
+	end int
+	//value is the text between the quotes with the escapes removed
+	value []rune
+}
+
+//quotedLiterals returns the quoted spans in the sentence.
+//A quote starts a literal only at the start of a word so that the apostrophes in the words like Maruti's are not considered.
+//The literal ends at the matching quote which is not escaped and not followed by a word.
+//A backslash escapes the quote and the backslash following it. Quotes without a matching quote are left as they are
+func quotedLiterals(sentence []rune) []literal {
+	result := []literal{}
+	for i := 0; i < len(sentence); i++ {
+		closing, ok := quotePairs[sentence[i]]
+		if !ok || (i > 0 && isWordRune(sentence[i-1])) {
+			continue
+		}
+
+		//finding the closing quote
+		value := []rune{}
+		for j := i + 1; j < len(sentence); j++ {
+			if sentence[j] == '\\' && j+1 < len(sentence) && (sentence[j+1] == closing || sentence[j+1] == '\\') {
+				value = append(value, sentence[j+1])
+				j++
+				continue
+			}
+			if sentence[j] == closing && (j+1 == len(sentence) || !isWordRune(sentence[j+1])) {
+				result = append(result, literal{start: i, end: j + 1, value: value})
+				i = j
+				break
+			}
+			value = append(value, sentence[j])
+		}
+	}
+	return result
+}
+
+//maskLiterals returns a copy of the sentence with the quoted literals replaced by spaces.
+//The positions in the masked sentence are same as the sentence, so the words, dates and numbers found in it refer to the sentence
+func maskLiterals(sentence []rune) []rune {
+	lits := quotedLiterals(sentence)
+	if len(lits) == 0 {
+		return sentence
+	}
+	result := make([]rune, len(sentence))
+	copy(result, sentence)
+	for _, l := range lits {
+		for i := l.start; i < l.end; i++ {
+			result[i] = ' '
+		}
+	}
+	return result
+}
+
+//BuildLiterals will add the quoted literals in the sentence as tokens with literal unknown nodes.
+//The word of the unknown node is the text between the quotes with the escapes removed.
+//Literals bypass the dictionary, so the existing tokens overlapping them are removed. The tokens are returned sorted by their position
+func BuildLiterals(sentence []rune, toks []Token) []Token {
+	/*
+	 * We will find the quoted literals in the sentence
+	 * Then we will remove the tokens overlapping the literals
+	 * Then the literals are added as tokens and sorted by their position
+	 */
+	lits := quotedLiterals(sentence)
+	if len(lits) == 0 {
+		return toks
+	}
+	literals := []Token{}
+	for i, l := range lits {
+		literals = append(literals, Token{
+			Pos:   l.start,
+			Word:  sentence[l.start:l.end],
+			Nodes: []Node{&UnknownNode{UID: "L" + strconv.Itoa(i), Word: l.value, Literal: true}},
+		})
+	}
+
+	//removing the overlapping tokens
+	result := []Token{}
+	for _, tok := range toks {
+		if overlapping(literals, tok) < 0 {
+			result = append(result, tok)
+		}
+	}
+	result = append(result, literals...)
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Pos < result[j].Pos })
+	return result
+}
@@ -0,0 +1,112 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package interpreter_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cuttle-ai/octopus/interpreter"
+	"github.com/cuttle-ai/octopus/testutils"
+)
+
+/*
+ * This file contains the tests for the quoted literals
+ */
+
+//literalDICT returns the dictionary for the literal tests
+func literalDICT() interpreter.DICT {
+	table := &interpreter.TableNode{UID: "literals", Name: "literals"}
+	car := &interpreter.ColumnNode{UID: "car", PUID: table.UID, PN: table, Name: "car", DataType: interpreter.DataTypeString}
+	swift := &interpreter.ValueNode{UID: "swift", PUID: car.UID, PN: car, Name: "Swift"}
+	is := &interpreter.OperatorNode{UID: "is", Operation: interpreter.EqOperator}
+	return interpreter.DICT{Map: map[string]interpreter.Token{
+		"car":   {Word: []rune("car"), Nodes: []interpreter.Node{car}},
+		"swift": {Word: []rune("swift"), Nodes: []interpreter.Node{swift}},
+		"is":    {Word: []rune("is"), Nodes: []interpreter.Node{is}},
+	}}
+}
+
+type literalTest struct {
+	testutils.Test
+	Input    string
+	Literals []string
+	Values   int
+}
+
+var literalTestcases = []literalTest{
+	{
+		Test:  testutils.Test{Name: "Double quotes", Description: "double quoted span is a single literal bypassing the dictionary"},
+		Input: `car is "Maruti Swift Dzire"`, Literals: []string{"Maruti Swift Dzire"},
+	},
+	{
+		Test:  testutils.Test{Name: "Single quotes", Description: "single quoted span is a single literal"},
+		Input: `car is 'Swift 2019' and swift`, Literals: []string{"Swift 2019"}, Values: 1,
+	},
+	{
+		Test:  testutils.Test{Name: "Escapes", Description: "escaped quotes and backslashes are unescaped in the literal"},
+		Input: `car is "The \"Swift\" \\ Dzire"`, Literals: []string{`The "Swift" \ Dzire`},
+	},
+	{
+		Test:  testutils.Test{Name: "Apostrophe", Description: "apostrophes inside the words doesn't start a literal"},
+		Input: `Maruti's swift car's`, Values: 1,
+	},
+	{
+		Test:  testutils.Test{Name: "Unterminated", Description: "quote without a matching quote is left as it is"},
+		Input: `car is "swift`, Values: 1,
+	},
+	{
+		Test:  testutils.Test{Name: "Empty", Description: "empty quotes are an empty literal"},
+		Input: `car is ""`, Literals: []string{""},
+	},
+}
+
+func TestTokenizeLiterals(t *testing.T) {
+	if err := interpreter.AddDICT("literal-user", literalDICT()); err != nil {
+		t.Fatal("error while adding the dictionary", err)
+	}
+	for _, v := range literalTestcases {
+		t.Run(v.Name, func(t *testing.T) {
+			toks, err := interpreter.TokenizeContext(context.Background(), "literal-user", []rune(v.Input))
+			if err != nil {
+				t.Fatal("error while tokenizing the sentence", err)
+			}
+			literals, values := []string{}, 0
+			for _, tok := range toks {
+				values += len(tok.Values)
+				for _, u := range tok.Unknowns {
+					if u.Literal {
+						literals = append(literals, string(u.Word))
+					}
+				}
+			}
+			if len(literals) != len(v.Literals) || values != v.Values {
+				t.Fatal("Expected the literals", v.Literals, "and", v.Values, "values. Got", literals, "and", values, "values in", toks)
+			}
+			for i := range literals {
+				if literals[i] != v.Literals[i] {
+					t.Error("Expected the literal", "`"+v.Literals[i]+"`", "got", "`"+literals[i]+"`")
+				}
+			}
+		})
+	}
+}
+
+func TestToSQLLiterals(t *testing.T) {
+	q := interpreter.Query{
+		Select: []interpreter.ColumnNode{testColumnCity},
+		Tables: map[string]interpreter.TableNode{"1": testTable},
+		Filters: []interpreter.OperatorNode{
+			{Operation: interpreter.EqOperator, Column: &testColumnCity, Unknown: &interpreter.UnknownNode{Word: []rune(" New Delhi "), Literal: true}},
+		},
+	}
+	s, err := q.ToSQL()
+	if err != nil {
+		t.Fatal("error while converting the query to sql", err)
+	}
+	if len(s.Args) != 1 || s.Args[0] != " New Delhi " {
+		t.Error("Expected the literal to be bound exactly. Got", s.Args)
+	}
+}
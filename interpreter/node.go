@@ -34,6 +34,10 @@ const (
 	Context Type = 10
 	//Time node represents a time data
 	Time Type = 11
+	//Rank node represents a superlative/ranking word based on which the results are sorted and limited
+	Rank Type = 12
+	//Number node represents a numeric literal like 1.5k, $20, 10%
+	Number Type = 13
 )
 
 //Node is the interface to be implemented for considering it as a basic building block in octopus
@@ -0,0 +1,326 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package interpreter
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+/*
+ * This file contains the normalisers applied to the dictionary words and the sentence before matching them
+ */
+
+//Normaliser normalises the words so that the different forms of a word match the same dictionary entry.
+//The same normaliser is applied to the words in the dictionary while building the tokenizer and to the sentence while tokenizing
+type Normaliser interface {
+	//Name is the name of the normaliser. Tokenizers built with different normalisers are not shared
+	Name() string
+	//Normalise returns the normal form of the lower cased word
+	Normalise(word []rune) []rune
+}
+
+//EnglishNormaliser normalises the english words by folding the plurals and mapping the common irregular forms to their lemma.
+//The singular and the plural of a word have the same normal form, which need not be a word by itself. Eg. movie and movies are movy
+type EnglishNormaliser struct{}
+
+//englishLemmas has the irregular forms of the words mapped to their normal form
+var englishLemmas = map[string]string{
+	"sold":     "sale",
+	"sell":     "sale",
+	"sells":    "sale",
+	"selling":  "sale",
+	"sales":    "sale",
+	"bought":   "buy",
+	"buys":     "buy",
+	"buying":   "buy",
+	"men":      "man",
+	"women":    "woman",
+	"people":   "person",
+	"children": "child",
+	"feet":     "foot",
+	"teeth":    "tooth",
+	"mice":     "mouse",
+	"geese":    "goose",
+	"does":     "does",
+	"goes":     "goes",
+	"yes":      "yes",
+	"news":     "news",
+	"series":   "series",
+	"species":  "species",
+}
+
+//Name returns the name of the normaliser
+func (e EnglishNormaliser) Name() string {
+	return "english"
+}
+
+//Normalise returns the normal form of the singular or the lemma of the word.
+//Words having anything other than letters are not changed
+func (e EnglishNormaliser) Normalise(word []rune) []rune {
+	/*
+	 * We will check whether the word has only letters
+	 * Then we will check for the irregular forms
+	 * Then the plural suffixes are removed
+	 * Then the endings of the singulars dropped while folding their plurals are removed
+	 */
+	for _, r := range word {
+		if !unicode.IsLetter(r) {
+			return word
+		}
+	}
+	w := string(word)
+	if lemma, ok := englishLemmas[w]; ok {
+		return []rune(lemma)
+	}
+
+	//plurals
+	n := len(word)
+	switch {
+	case n > 4 && strings.HasSuffix(w, "ies"):
+		return []rune(w[:len(w)-3] + "y")
+	case n > 4 && strings.HasSuffix(w, "es") && endsWithSibilant(w[:len(w)-2]):
+		return word[:n-2]
+	case n > 3 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss") && !strings.HasSuffix(w, "us") && !strings.HasSuffix(w, "is"):
+		return word[:n-1]
+	}
+
+	//singulars like movie, cache and house
+	switch {
+	case n > 3 && strings.HasSuffix(w, "ie"):
+		return []rune(w[:len(w)-2] + "y")
+	case n > 3 && strings.HasSuffix(w, "e") && endsWithSibilant(w[:len(w)-1]):
+		return word[:n-1]
+	}
+	return word
+}
+
+//endsWithSibilant returns true if the word ends with a sibilant after which the plural suffix is es
+func endsWithSibilant(w string) bool {
+	return strings.HasSuffix(w, "s") || strings.HasSuffix(w, "x") || strings.HasSuffix(w, "z") || strings.HasSuffix(w, "ch") || strings.HasSuffix(w, "sh")
+}
+
+//DefaultNormaliser is the default normaliser used by the tokenizer
+var DefaultNormaliser Normaliser = EnglishNormaliser{}
+
+//normalised is a normalised text along with the span of each of its runes in the original text
+type normalised struct {
+	//text is the normalised text
+	text []rune
+	//start is the start of the span in the original text of each rune
+	start []int
+	//end is the end of the span in the original text of each rune
+	end []int
+}
+
+//normaliseText lower cases the text and normalises its words with the normaliser. If the normaliser is nil, the text is only lower cased.
+//Runes of a changed word refer to the whole word in the original text while the others refer to themselves
+func normaliseText(text []rune, n Normaliser) normalised {
+	result := normalised{text: make([]rune, 0, len(text)), start: make([]int, 0, len(text)), end: make([]int, 0, len(text))}
+	for i := 0; i < len(text); {
+		//runes which are not part of a word are only lower cased
+		if !isWordRune(text[i]) {
+			result.text = append(result.text, unicode.ToLower(text[i]))
+			result.start = append(result.start, i)
+			result.end = append(result.end, i+1)
+			i++
+			continue
+		}
+
+		//finding the word
+		j := i
+		word := []rune{}
+		for j < len(text) && isWordRune(text[j]) {
+			word = append(word, unicode.ToLower(text[j]))
+			j++
+		}
+
+		//normalising the word
+		norm := word
+		if n != nil {
+			norm = n.Normalise(word)
+		}
+		if len(norm) == 0 || string(norm) == string(word) {
+			for k, r := range word {
+				result.text = append(result.text, r)
+				result.start = append(result.start, i+k)
+				result.end = append(result.end, i+k+1)
+			}
+		} else {
+			for _, r := range norm {
+				result.text = append(result.text, r)
+				result.start = append(result.start, i)
+				result.end = append(result.end, j)
+			}
+		}
+		i = j
+	}
+	return result
+}
+
+//span returns the span in the original text of the given no. of runes starting at the position in the normalised text
+func (n normalised) span(pos, length int) (int, int) {
+	return n.start[pos], n.end[pos+length-1]
+}
+
+//normaliseWord returns the normalised form of the word in the dictionary. It is same as the text of normaliseText
+func normaliseWord(word string, n Normaliser) string {
+	b := strings.Builder{}
+	b.Grow(len(word))
+	runes := []rune(word)
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			b.WriteRune(unicode.ToLower(runes[i]))
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && isWordRune(runes[j]) {
+			runes[j] = unicode.ToLower(runes[j])
+			j++
+		}
+		w := runes[i:j]
+		if n != nil {
+			if norm := n.Normalise(w); len(norm) != 0 {
+				w = norm
+			}
+		}
+		for _, r := range w {
+			b.WriteRune(r)
+		}
+		i = j
+	}
+	return b.String()
+}
+
+//normalisedWords returns the tokens of the dictionary mapped to their normalised words. Only the nodes of the tokens are kept.
+//The nodes of the tokens having the same normalised word are merged in the order of their words in the dictionary
+func normalisedWords(dict map[string]Token, n Normaliser) map[string]Token {
+	/*
+	 * We will map the dictionary words to their normalised word
+	 * The words having the same normalised word are grouped
+	 * Then the nodes of the groups are merged
+	 */
+	result := make(map[string]Token, len(dict))
+	words := make(map[string]string, len(dict))
+	groups := map[string][]string{}
+	for word, tok := range dict {
+		if len(word) == 0 {
+			continue
+		}
+		key := normaliseWord(word, n)
+		if first, ok := words[key]; ok {
+			if len(groups[key]) == 0 {
+				groups[key] = []string{first}
+			}
+			groups[key] = append(groups[key], word)
+			continue
+		}
+		words[key] = word
+		result[key] = Token{Nodes: tok.Nodes}
+	}
+
+	//merging the nodes
+	for key, words := range groups {
+		sort.Strings(words)
+		nodes := []Node{}
+		for _, word := range words {
+			for _, node := range dict[word].Nodes {
+				found := false
+				for _, e := range nodes {
+					if e == node {
+						found = true
+						break
+					}
+				}
+				if !found {
+					nodes = append(nodes, node)
+				}
+			}
+		}
+		result[key] = Token{Nodes: nodes}
+	}
+
+	indexAliases(result, dict, n)
+	return result
+}
+
+//nodeAliases returns the aliases of the node. Only the table, column and value nodes can have aliases
+func nodeAliases(n Node) []string {
+	switch node := n.(type) {
+	case *TableNode:
+		return node.Aliases
+	case *ColumnNode:
+		return node.Aliases
+	case *ValueNode:
+		return node.Aliases
+	}
+	return nil
+}
+
+//aliasEntry is an alias of a node to be indexed
+type aliasEntry struct {
+	alias string
+	node  Node
+}
+
+//indexAliases adds the aliases of the nodes in the dictionary to the normalised words.
+//The aliases point to the same node instance and the alias matched is recorded in the matched aliases of the token
+func indexAliases(words map[string]Token, dict map[string]Token, n Normaliser) {
+	/*
+	 * We will find the aliases of the nodes in the dictionary grouped by their normalised word
+	 * Then the nodes are added to the tokens of the normalised words in a deterministic order
+	 * A node already present in the token is not added again
+	 */
+	aliases := map[string][]aliasEntry{}
+	for _, tok := range dict {
+		for _, node := range tok.Nodes {
+			for _, alias := range nodeAliases(node) {
+				if len(alias) == 0 {
+					continue
+				}
+				key := normaliseWord(alias, n)
+				aliases[key] = append(aliases[key], aliasEntry{alias: alias, node: node})
+			}
+		}
+	}
+
+	//adding the aliases
+	for key, entries := range aliases {
+		sort.SliceStable(entries, func(i, j int) bool {
+			if entries[i].alias != entries[j].alias {
+				return entries[i].alias < entries[j].alias
+			}
+			return entries[i].node.ID() < entries[j].node.ID()
+		})
+		tok := words[key]
+		nodes := append([]Node{}, tok.Nodes...)
+		matched := make([]string, len(tok.Nodes), len(tok.Nodes)+len(entries))
+		copy(matched, tok.MatchedAliases)
+		for _, e := range entries {
+			found := false
+			for _, existing := range nodes {
+				if existing == e.node {
+					found = true
+					break
+				}
+			}
+			if !found {
+				nodes = append(nodes, e.node)
+				matched = append(matched, e.alias)
+			}
+		}
+		words[key] = Token{Nodes: nodes, MatchedAliases: matched}
+	}
+}
+
+//normaliserName returns the name of the normaliser. It is empty for nil normaliser
+func normaliserName(n Normaliser) string {
+	if n == nil {
+		return ""
+	}
+	return n.Name()
+}
@@ -0,0 +1,90 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package interpreter_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cuttle-ai/octopus/interpreter"
+	"github.com/cuttle-ai/octopus/testutils"
+)
+
+/*
+ * This file contains the tests for the normalisers
+ */
+
+type englishNormaliserTest struct {
+	testutils.Test
+	Input    string
+	Expected string
+}
+
+var englishNormaliserTestcases = []englishNormaliserTest{
+	{Test: testutils.Test{Name: "Plural", Description: "plurals ending with s are folded"}, Input: "brands", Expected: "brand"},
+	{Test: testutils.Test{Name: "Plural ies", Description: "plurals ending with ies are folded to y"}, Input: "categories", Expected: "category"},
+	{Test: testutils.Test{Name: "Plural es", Description: "plurals ending with es after a sibilant are folded"}, Input: "branches", Expected: "branch"},
+	{Test: testutils.Test{Name: "Irregular", Description: "irregular forms are mapped to their lemma"}, Input: "sold", Expected: "sale"},
+	{Test: testutils.Test{Name: "Lemma", Description: "words having a lemma are mapped to it"}, Input: "sales", Expected: "sale"},
+	{Test: testutils.Test{Name: "Not plural", Description: "words ending with ss, us and is are not folded"}, Input: "status", Expected: "status"},
+	{Test: testutils.Test{Name: "Short words", Description: "short words are not folded"}, Input: "has", Expected: "has"},
+	{Test: testutils.Test{Name: "Digits", Description: "words with digits are not changed"}, Input: "100s", Expected: "100s"},
+	{Test: testutils.Test{Name: "Plural ies of ie", Description: "plurals ending with ies of words ending with ie are folded to y"}, Input: "movies", Expected: "movy"},
+	{Test: testutils.Test{Name: "Singular ie", Description: "words ending with ie are folded like their plurals"}, Input: "movie", Expected: "movy"},
+	{Test: testutils.Test{Name: "Plural ches of che", Description: "plurals ending with ches of words ending with che are folded"}, Input: "caches", Expected: "cach"},
+	{Test: testutils.Test{Name: "Singular che", Description: "words ending with che are folded like their plurals"}, Input: "cache", Expected: "cach"},
+	{Test: testutils.Test{Name: "Plural ses", Description: "plurals ending with ses are folded"}, Input: "buses", Expected: "bus"},
+	{Test: testutils.Test{Name: "Singular us", Description: "words ending with us are folded like their plurals"}, Input: "bus", Expected: "bus"},
+	{Test: testutils.Test{Name: "Plural ses of se", Description: "plurals ending with ses of words ending with se are folded"}, Input: "houses", Expected: "hous"},
+	{Test: testutils.Test{Name: "Singular se", Description: "words ending with se are folded like their plurals"}, Input: "house", Expected: "hous"},
+}
+
+func TestEnglishNormaliser(t *testing.T) {
+	n := interpreter.EnglishNormaliser{}
+	for _, v := range englishNormaliserTestcases {
+		t.Run(v.Name, func(t *testing.T) {
+			if got := string(n.Normalise([]rune(v.Input))); got != v.Expected {
+				t.Error("Expected", v.Expected, "got", got)
+			}
+		})
+	}
+}
+
+func TestTokenizeNormalised(t *testing.T) {
+	table := &interpreter.TableNode{UID: "normalise", Name: "normalise"}
+	brand := &interpreter.ColumnNode{UID: "brand", PUID: table.UID, PN: table, Name: "brand"}
+	car := &interpreter.ColumnNode{UID: "car", PUID: table.UID, PN: table, Name: "car"}
+	sales := &interpreter.ColumnNode{UID: "sales", PUID: table.UID, PN: table, Name: "sales"}
+	err := interpreter.AddDICT("normalise-user", interpreter.DICT{Map: map[string]interpreter.Token{
+		"brand": {Word: []rune("brand"), Nodes: []interpreter.Node{brand}},
+		"car":   {Word: []rune("car"), Nodes: []interpreter.Node{car}},
+		"sales": {Word: []rune("sales"), Nodes: []interpreter.Node{sales}},
+	}})
+	if err != nil {
+		t.Fatal("error while adding the dictionary", err)
+	}
+	toks, err := interpreter.TokenizeContext(context.Background(), "normalise-user", []rune("Brands of Cars sold"))
+	if err != nil {
+		t.Fatal("error while tokenizing the sentence", err)
+	}
+	expected := []struct {
+		Word   string
+		Column string
+	}{{"Brands", "brand"}, {"of ", ""}, {"Cars", "car"}, {"sold", "sales"}}
+	if len(toks) != len(expected) {
+		t.Fatal("Expected", len(expected), "tokens. Got", toks)
+	}
+	for i, e := range expected {
+		if string(toks[i].Word) != e.Word {
+			t.Error("Expected the word", e.Word, "from the sentence at", i, "got", string(toks[i].Word))
+		}
+		if len(e.Column) == 0 {
+			continue
+		}
+		if len(toks[i].Columns) != 1 || toks[i].Columns[0].UID != e.Column {
+			t.Error("Expected the column", e.Column, "for", e.Word, "got", toks[i].Columns)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package interpreter
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+ * This file contains the defnition of number type node and the utilities for recognising the numbers in a sentence
+ */
+
+//NumberNode is the node storing a numeric literal in the sentence like 1.5k, $20, 10%, 2 crore.
+//Value has the number with the thousands separators removed and the multiplier of the suffix applied
+type NumberNode struct {
+	//UID is the unique id of the number node
+	UID string
+	//Word is the word in the sentence from which the number was recognised
+	Word []rune
+	//PUID is the UID of number's parent node
+	PUID string
+	//PN is the parent node of the number. It will be a KnowledgeBase
+	PN Node
+	//Resolved indicates that the node is resolved
+	Resolved bool
+	//Value is the value of the number
+	Value float64
+	//Currency is the currency symbol or code preceding the number. It is empty if the number is not an amount
+	Currency string
+	//Percent indicates that the number is a percentage. The value is not divided by 100, so 10% has the value 10
+	Percent bool
+}
+
+type numberNode struct {
+	UID      string  `json:"uid,omitempty"`
+	Word     string  `json:"word,omitempty"`
+	PUID     string  `json:"puid,omitempty"`
+	Resolved bool    `json:"resolved,omitempty"`
+	Type     string  `json:"type,omitempty"`
+	Value    float64 `json:"value"`
+	Currency string  `json:"currency,omitempty"`
+	Percent  bool    `json:"percent,omitempty"`
+}
+
+//Copy will return a copy of the node
+func (n *NumberNode) Copy() Node {
+	return &NumberNode{
+		UID:      n.UID,
+		Word:     n.Word,
+		PN:       n.PN,
+		PUID:     n.PUID,
+		Resolved: n.Resolved,
+		Value:    n.Value,
+		Currency: n.Currency,
+		Percent:  n.Percent,
+	}
+}
+
+//ID returns the unique id of the node
+func (n *NumberNode) ID() string {
+	return n.UID
+}
+
+//Type returns Number Type
+func (n *NumberNode) Type() Type {
+	return Number
+}
+
+//TokenWord returns the word property of the node
+func (n *NumberNode) TokenWord() []rune {
+	return n.Word
+}
+
+//PID returns the PUID if the node
+func (n *NumberNode) PID() string {
+	return n.PUID
+}
+
+//Parent returns the PN of the node
+func (n *NumberNode) Parent() Node {
+	return n.PN
+}
+
+//MarshalJSON encodes the node into a serializable json
+func (n *NumberNode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&numberNode{
+		n.UID, string(n.Word), n.PUID, n.Resolved, "Number", n.Value, n.Currency, n.Percent,
+	})
+}
+
+//UnmarshalJSON decodes the node from a json
+func (n *NumberNode) UnmarshalJSON(data []byte) error {
+	m := &numberNode{}
+	err := json.Unmarshal(data, m)
+	if err != nil {
+		return err
+	}
+	n.UID = m.UID
+	n.Word = []rune(m.Word)
+	n.PUID = m.PUID
+	n.Resolved = m.Resolved
+	n.Value = m.Value
+	n.Currency = m.Currency
+	n.Percent = m.Percent
+	return nil
+}
+
+//IsResolved will return true if the node is resolved
+func (n *NumberNode) IsResolved() bool {
+	return n.Resolved
+}
+
+//SetResolved will set the resolved state of the node
+func (n *NumberNode) SetResolved(state bool) {
+	n.Resolved = state
+}
+
+//IsInteger returns true if the number doesn't have a fractional part
+func (n *NumberNode) IsInteger() bool {
+	return n.Value == math.Trunc(n.Value) && !math.IsInf(n.Value, 0)
+}
+
+//Arg returns the value of the number to be bound as an argument for the column of the given data type.
+//Integers are returned for the integer columns if the number doesn't have a fractional part. Numbers can't be used with the date columns
+func (n *NumberNode) Arg(dataType string) (interface{}, bool) {
+	switch dataType {
+	case DataTypeInt:
+		if n.IsInteger() {
+			return int64(n.Value), true
+		}
+		return n.Value, true
+	case DataTypeFloat:
+		return n.Value, true
+	case DataTypeString:
+		return strings.TrimSpace(string(n.Word)), true
+	}
+	return nil, false
+}
+
+//numberMultipliers has the multiplier of each suffix
+var numberMultipliers = map[string]float64{
+	"k":        1e3,
+	"thousand": 1e3,
+	"m":        1e6,
+	"mn":       1e6,
+	"million":  1e6,
+	"b":        1e9,
+	"bn":       1e9,
+	"billion":  1e9,
+	"lakh":     1e5,
+	"lakhs":    1e5,
+	"lac":      1e5,
+	"lacs":     1e5,
+	"cr":       1e7,
+	"crore":    1e7,
+	"crores":   1e7,
+}
+
+//numberPattern recognises the numbers with the optional currency, thousands separators, suffix and percentage.
+//Thousands can be separated in the international (1,000,000) and the indian (10,00,000) system.
+//Single letter suffixes has to be attached to the number while the words can be separated by a space
+var numberPattern = regexp.MustCompile(`(?i)([$€£₹]|\b(?:rs|inr|usd|eur)\.?\s?)?` +
+	`(\d{1,3}(?:,\d{3})+(?:\.\d+)?|\d{1,2}(?:,\d{2})*,\d{3}(?:\.\d+)?|\d+(?:\.\d+)?|\.\d+)` +
+	`(?:(k|mn|m|bn|b|cr)\b|\s?(thousand|million|billion|lakhs|lakh|lacs|lac|crores|crore)\b|\s?(%|percent\b))?`)
+
+//ParseNumber returns the number node for the given word if it is a number. Else false is returned
+func ParseNumber(word string) (*NumberNode, bool) {
+	w := strings.TrimSpace(word)
+	m := numberPattern.FindStringSubmatchIndex(w)
+	if m == nil || m[0] != 0 || m[1] != len(w) {
+		return nil, false
+	}
+	return numberFromMatch(w, m), true
+}
+
+//numberFromMatch returns the number node from the submatch indexes of the number pattern
+func numberFromMatch(s string, m []int) *NumberNode {
+	n := &NumberNode{Word: []rune(s[m[0]:m[1]])}
+	if m[2] >= 0 {
+		n.Currency = strings.ToUpper(strings.TrimRight(strings.TrimSpace(s[m[2]:m[3]]), "."))
+	}
+	n.Value, _ = strconv.ParseFloat(strings.Replace(s[m[4]:m[5]], ",", "", -1), 64)
+	for _, g := range []int{6, 8} {
+		if m[g] >= 0 {
+			n.Value *= numberMultipliers[strings.ToLower(s[m[g]:m[g+1]])]
+		}
+	}
+	if m[10] >= 0 {
+		n.Percent = true
+	}
+	return n
+}
+
+//BuildNumberNodes will find the numbers in the sentence and add them as tokens with number nodes.
+//Numbers overlapping the existing tokens like the dictionary words and the times are skipped.
+//The tokens are returned sorted by their position in the sentence
+func BuildNumberNodes(sentence []rune, toks []Token) []Token {
+	/*
+	 * We will find the numbers in the sentence
+	 * Numbers which are part of a word or overlapping an existing token are skipped
+	 * Then the tokens are sorted by their position
+	 */
+	s := string(sentence)
+	result := append([]Token{}, toks...)
+	for i, m := range numberPattern.FindAllStringSubmatchIndex(s, -1) {
+		//the number has to start and end at the word boundaries
+		if r, _ := utf8.DecodeLastRuneInString(s[:m[0]]); m[0] > 0 && (isWordRune(r) || r == '.' || r == ',') {
+			continue
+		}
+		if r, _ := utf8.DecodeRuneInString(s[m[1]:]); m[1] < len(s) && isWordRune(r) {
+			continue
+		}
+
+		//checking the overlap with the existing tokens
+		n := numberFromMatch(s, m)
+		n.UID = "N" + strconv.Itoa(i)
+		tok := Token{Pos: utf8.RuneCountInString(s[:m[0]]), Word: n.Word, Nodes: []Node{n}}
+		if overlapping(toks, tok) >= 0 {
+			continue
+		}
+		result = append(result, tok)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Pos < result[j].Pos })
+	return result
+}
@@ -0,0 +1,118 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package interpreter_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cuttle-ai/octopus/interpreter"
+	"github.com/cuttle-ai/octopus/testutils"
+)
+
+/*
+ * This file contains the tests for the number nodes
+ */
+
+type parseNumberTest struct {
+	testutils.Test
+	Input    string
+	Value    float64
+	Currency string
+	Percent  bool
+	Invalid  bool
+}
+
+var parseNumberTestcases = []parseNumberTest{
+	{Test: testutils.Test{Name: "Integer", Description: "plain integers are numbers"}, Input: "42", Value: 42},
+	{Test: testutils.Test{Name: "Decimal", Description: "decimals are numbers"}, Input: "3.75", Value: 3.75},
+	{Test: testutils.Test{Name: "Thousands", Description: "thousands separators are removed"}, Input: "1,000", Value: 1000},
+	{Test: testutils.Test{Name: "Indian thousands", Description: "thousands separators of the indian system are removed"}, Input: "10,00,000", Value: 1000000},
+	{Test: testutils.Test{Name: "Suffix k", Description: "k suffix multiplies by thousand"}, Input: "1.5k", Value: 1500},
+	{Test: testutils.Test{Name: "Suffix word", Description: "suffix words can be separated by a space"}, Input: "2 million", Value: 2000000},
+	{Test: testutils.Test{Name: "Suffix bn", Description: "bn suffix multiplies by billion"}, Input: "2.5bn", Value: 2500000000},
+	{Test: testutils.Test{Name: "Lakh", Description: "lakh multiplies by hundred thousand"}, Input: "5 lakh", Value: 500000},
+	{Test: testutils.Test{Name: "Crore", Description: "crore multiplies by ten million"}, Input: "3 crore", Value: 30000000},
+	{Test: testutils.Test{Name: "Currency symbol", Description: "currency symbols are recorded"}, Input: "$20", Value: 20, Currency: "$"},
+	{Test: testutils.Test{Name: "Currency code", Description: "currency codes are recorded in upper case"}, Input: "Rs. 1,500", Value: 1500, Currency: "RS"},
+	{Test: testutils.Test{Name: "Percent", Description: "percentages are not divided by hundred"}, Input: "10%", Value: 10, Percent: true},
+	{Test: testutils.Test{Name: "Not a number", Description: "words are not numbers"}, Input: "swift", Invalid: true},
+	{Test: testutils.Test{Name: "Attached word", Description: "numbers followed by letters are not numbers"}, Input: "100s", Invalid: true},
+}
+
+func TestParseNumber(t *testing.T) {
+	for _, v := range parseNumberTestcases {
+		t.Run(v.Name, func(t *testing.T) {
+			n, ok := interpreter.ParseNumber(v.Input)
+			if ok == v.Invalid {
+				t.Fatal("Expected the number to be parsed", !v.Invalid, "got", ok)
+			}
+			if v.Invalid {
+				return
+			}
+			if n.Value != v.Value || n.Currency != v.Currency || n.Percent != v.Percent {
+				t.Error("Expected", v.Value, v.Currency, v.Percent, "got", n.Value, n.Currency, n.Percent)
+			}
+		})
+	}
+}
+
+func TestTokenizeNumbers(t *testing.T) {
+	table := &interpreter.TableNode{UID: "numbers", Name: "numbers"}
+	sales := &interpreter.ColumnNode{UID: "sales", PUID: table.UID, PN: table, Name: "sales", DataType: interpreter.DataTypeInt}
+	model := &interpreter.ValueNode{UID: "model-800", PUID: sales.UID, PN: sales, Name: "800 series"}
+	more := &interpreter.OperatorNode{UID: "more-than", Operation: interpreter.GreaterOperator}
+	err := interpreter.AddDICT("numbers-user", interpreter.DICT{Map: map[string]interpreter.Token{
+		"sales":      {Word: []rune("sales"), Nodes: []interpreter.Node{sales}},
+		"800 series": {Word: []rune("800 series"), Nodes: []interpreter.Node{model}},
+		"more than":  {Word: []rune("more than"), Nodes: []interpreter.Node{more}},
+	}})
+	if err != nil {
+		t.Fatal("error while adding the dictionary", err)
+	}
+	toks, err := interpreter.TokenizeContext(context.Background(), "numbers-user", []rune("sales more than 1.5k of 800 series"))
+	if err != nil {
+		t.Fatal("error while tokenizing the sentence", err)
+	}
+	numbers, values := []interpreter.NumberNode{}, 0
+	for _, tok := range toks {
+		numbers = append(numbers, tok.Numbers...)
+		values += len(tok.Values)
+	}
+	if len(numbers) != 1 || numbers[0].Value != 1500 || string(numbers[0].Word) != "1.5k" {
+		t.Error("Expected the number 1.5k to be recognised as 1500. Got", numbers)
+	}
+	if values != 1 {
+		t.Error("Expected the numbers in the dictionary words not to be recognised as numbers. Got", toks)
+	}
+	if pattern := interpreter.BuildPattern(toks); len(pattern) < 3 || pattern[2] != interpreter.Number {
+		t.Error("Expected the number to be in the pattern of the tokens. Got", pattern)
+	}
+}
+
+func TestToSQLNumbers(t *testing.T) {
+	column := interpreter.ColumnNode{Name: "amount", DataType: interpreter.DataTypeInt}
+	price := interpreter.ColumnNode{Name: "price", DataType: interpreter.DataTypeFloat}
+	q := interpreter.Query{
+		Select: []interpreter.ColumnNode{column},
+		Tables: map[string]interpreter.TableNode{"1": testTable},
+		Filters: []interpreter.OperatorNode{
+			{Operation: interpreter.GreaterOperator, Column: &column, Number: &interpreter.NumberNode{Word: []rune("2 million"), Value: 2000000}},
+			{Operation: interpreter.BetweenOperator, Column: &price, Number: &interpreter.NumberNode{Word: []rune("$1.5"), Value: 1.5, Currency: "$"}, ToNumber: &interpreter.NumberNode{Word: []rune("$20"), Value: 20, Currency: "$"}},
+			{Operation: interpreter.LessOperator, Column: &column, Unknown: &interpreter.UnknownNode{Word: []rune("1,000")}},
+		},
+	}
+	s, err := q.ToSQL()
+	if err != nil {
+		t.Fatal("error while converting the query to sql", err)
+	}
+	expected := `SELECT "amount" FROM "stores" WHERE "amount" >= $1 AND "price" BETWEEN $2 AND $3 AND "amount" <= $4`
+	if s.Query != expected {
+		t.Error("Expected query", "`"+expected+"`", "got", "`"+s.Query+"`")
+	}
+	if len(s.Args) != 4 || s.Args[0] != int64(2000000) || s.Args[1] != 1.5 || s.Args[2] != float64(20) || s.Args[3] != int64(1000) {
+		t.Error("Expected the args to be typed numbers. Got", s.Args)
+	}
+}
@@ -28,6 +28,12 @@ const ContainsOperator = "HAS"
 //LikeOperator indicates like operator
 const LikeOperator = "LIKE"
 
+//BetweenOperator indicates between operator. The column is matched against an inclusive range of two operands
+const BetweenOperator = "BETWEEN"
+
+//InOperator indicates in operator. The column is matched against a list of values
+const InOperator = "IN"
+
 //OperatorNode is the node storing the information about a operator.
 //Filters are set based on this node. It depends upon a column and value/unknown
 type OperatorNode struct {
@@ -47,8 +53,20 @@ type OperatorNode struct {
 	Unknown *UnknownNode
 	//Value is the value to be applied to the column node with the operator
 	Value *ValueNode
+	//Values is the list of values to be applied to the column node with the in operator
+	Values []ValueNode
 	//Time is the time node to be applied to the column node with the operator
 	Time *TimeNode
+	//Number is the number to be applied to the column node with the operator
+	Number *NumberNode
+	//ToUnknown is the upper bound of the range when the operator is between and the bound is an unknown
+	ToUnknown *UnknownNode
+	//ToValue is the upper bound of the range when the operator is between and the bound is a value
+	ToValue *ValueNode
+	//ToTime is the upper bound of the range when the operator is between and the bound is a time
+	ToTime *TimeNode
+	//ToNumber is the upper bound of the range when the operator is between and the bound is a number
+	ToNumber *NumberNode
 	//Operation is the operation applied by the node
 	Operation string
 }
@@ -60,10 +78,16 @@ type operatorNode struct {
 	Column    *ColumnNode  `json:"column,omitempty"`
 	Unknown   *UnknownNode `json:"unknown,omitempty"`
 	Value     *ValueNode   `json:"value,omitempty"`
+	Values    []ValueNode  `json:"values,omitempty"`
 	Time      *TimeNode    `json:"time,omitempty"`
+	ToUnknown *UnknownNode `json:"to_unknown,omitempty"`
+	ToValue   *ValueNode   `json:"to_value,omitempty"`
+	ToTime    *TimeNode    `json:"to_time,omitempty"`
 	Resolved  bool         `json:"resolved,omitempty"`
 	Type      string       `json:"type,omitempty"`
 	Operation string       `json:"operation,omitempty"`
+	Number    *NumberNode  `json:"number,omitempty"`
+	ToNumber  *NumberNode  `json:"to_number,omitempty"`
 }
 
 //Copy will return a copy of the node
@@ -76,9 +100,15 @@ func (o *OperatorNode) Copy() Node {
 		Resolved:  o.Resolved,
 		Column:    o.Column,
 		Value:     o.Value,
+		Values:    o.Values,
 		Time:      o.Time,
 		Unknown:   o.Unknown,
+		ToUnknown: o.ToUnknown,
+		ToValue:   o.ToValue,
+		ToTime:    o.ToTime,
 		Operation: o.Operation,
+		Number:    o.Number,
+		ToNumber:  o.ToNumber,
 	}
 }
 
@@ -110,7 +140,8 @@ func (o *OperatorNode) Parent() Node {
 //MarshalJSON encodes the node into a serializable json
 func (o *OperatorNode) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&operatorNode{
-		o.UID, string(o.Word), o.PUID, o.Column, o.Unknown, o.Value, o.Time, o.Resolved, "Operator", o.Operation,
+		o.UID, string(o.Word), o.PUID, o.Column, o.Unknown, o.Value, o.Values, o.Time, o.ToUnknown, o.ToValue, o.ToTime, o.Resolved, "Operator", o.Operation,
+		o.Number, o.ToNumber,
 	})
 }
 
@@ -127,9 +158,15 @@ func (o *OperatorNode) UnmarshalJSON(data []byte) error {
 	o.Column = m.Column
 	o.Unknown = m.Unknown
 	o.Value = m.Value
+	o.Values = m.Values
 	o.Time = m.Time
+	o.ToUnknown = m.ToUnknown
+	o.ToValue = m.ToValue
+	o.ToTime = m.ToTime
 	o.Resolved = m.Resolved
 	o.Operation = m.Operation
+	o.Number = m.Number
+	o.ToNumber = m.ToNumber
 	return nil
 }
 
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 )
 
 /*
@@ -25,8 +26,48 @@ type Query struct {
 	GroupBy []ColumnNode `json:"group_by,omitempty"`
 	//Filters has the list of filters applied in the query
 	Filters []OperatorNode `json:"filters,omitempty"`
+	//FilterGroups has the groups of filters combined with AND/OR/NOT. They are applied along with the filters
+	FilterGroups []FilterGroup `json:"filter_groups,omitempty"`
+	//Having has the list of filters applied on the aggregated measures in a grouped query
+	Having []OperatorNode `json:"having,omitempty"`
+	//HavingGroups has the groups of filters on the aggregated measures in a grouped query. They are applied along with the having filters
+	HavingGroups []FilterGroup `json:"having_groups,omitempty"`
+	//OrderBy has the list of columns based on which the results has to be sorted
+	OrderBy []Ordering `json:"order_by,omitempty"`
+	//Limit is the maximum no. of results to be returned. 0 indicates no limit
+	Limit int `json:"limit,omitempty"`
 	//Result has the result of the query
 	Result []map[string]interface{} `json:"result,omitempty"`
+	//Diagnostics explains why the parts of the sentence were ignored while interpreting the query
+	Diagnostics []Diagnostic `json:"diagnostics,omitempty"`
+}
+
+const (
+	//AndLogic combines the filters in a group with AND
+	AndLogic = "AND"
+	//OrLogic combines the filters in a group with OR
+	OrLogic = "OR"
+	//NotLogic negates the filters in a group combined with AND
+	NotLogic = "NOT"
+)
+
+//FilterGroup is a node in the boolean filter tree of a query.
+//The filters and the sub groups in a group are combined with the logic of the group
+type FilterGroup struct {
+	//Logic is the logic with which the filters in the group are combined. It can be AND, OR or NOT
+	Logic string `json:"logic,omitempty"`
+	//Filters in the group
+	Filters []OperatorNode `json:"filters,omitempty"`
+	//Groups are the sub groups in the group
+	Groups []FilterGroup `json:"groups,omitempty"`
+}
+
+//Ordering has the column and the direction in which the results has to be sorted
+type Ordering struct {
+	//Column based on which the results has to be sorted
+	Column ColumnNode `json:"column,omitempty"`
+	//Descending indicates that the results has to be sorted in descending order
+	Descending bool `json:"descending,omitempty"`
 }
 
 //SQLQuery stores a sql query to be executed
@@ -35,32 +76,41 @@ type SQLQuery struct {
 	Query string
 	//Args has the arguments to be passed to the query string
 	Args []interface{}
+	//Diagnostics has the filters dropped from the query as they were not valid
+	Diagnostics []Diagnostic
 }
 
-//ToSQL converts the the query to a sql query
+//ToSQL converts the the query to a sql query in the default dialect
 func (q Query) ToSQL() (*SQLQuery, error) {
+	return q.ToSQLWithDialect(DefaultDialect)
+}
+
+//ToSQLWithDialect converts the the query to a sql query in the given dialect
+func (q Query) ToSQLWithDialect(d Dialect) (*SQLQuery, error) {
 	/*
 	 * We will add check for zero table
 	 * If the no of tables is one we will choose the single table query mode
+	 * Else we will go for the multi table query mode
 	 */
 	if len(q.Tables) == 0 {
 		return nil, errors.New("couldn't find any tables")
 	}
 	if len(q.Tables) == 1 {
-		return q.ToSingleTableSQL()
+		return q.toSingleTableSQL(d)
 	}
-	return nil, errors.New("couldn't convert the query to sql as no of tables not suppoerted")
+	return q.toMultiTableSQL(d)
 }
 
 //ToSingleTableSQL will convert the query to sql if the query has only one table
 func (q Query) ToSingleTableSQL() (*SQLQuery, error) {
+	return q.toSingleTableSQL(DefaultDialect)
+}
+
+func (q Query) toSingleTableSQL(d Dialect) (*SQLQuery, error) {
 	/*
 	 * We will add a table check
 	 * Then we will get the table
-	 * Then we will iterate through the select fields
-	 * If group by fields are there, will add them to be selected
-	 * Then we will add the filters
-	 * Then we will add the group by if any
+	 * Then we will build the query with the table as the source
 	 */
 	//adding the table number check
 	if len(q.Tables) != 1 {
@@ -73,6 +123,82 @@ func (q Query) ToSingleTableSQL() (*SQLQuery, error) {
 		tableNode = v
 	}
 
+	b := &sqlBuilder{dialect: d}
+	return q.buildSQL(b, d.QuoteIdentifier(tableNode.Name))
+}
+
+//ToMultiTableSQL will convert the query to sql if the query has more than one table.
+//The tables are joined based on the foreign keys between them and the columns are qualified with their table names
+func (q Query) ToMultiTableSQL() (*SQLQuery, error) {
+	return q.toMultiTableSQL(DefaultDialect)
+}
+
+func (q Query) toMultiTableSQL(d Dialect) (*SQLQuery, error) {
+	/*
+	 * We will find the join path between the tables
+	 * Then we will build the source with the joins
+	 * Then we will build the query with the qualified column names
+	 */
+	//finding the join path
+	root, joins, err := q.JoinPath()
+	if err != nil {
+		return nil, err
+	}
+
+	//building the source
+	b := &sqlBuilder{dialect: d, tables: q.Tables}
+	var fromB strings.Builder
+	fromB.WriteString(d.QuoteIdentifier(root.Name))
+	for _, j := range joins {
+		fromB.WriteString(" JOIN " + d.QuoteIdentifier(j.Table.Name) +
+			" ON " + d.QuoteIdentifier(j.Table.Name) + "." + d.QuoteIdentifier(j.Column) +
+			" = " + d.QuoteIdentifier(j.RefTable.Name) + "." + d.QuoteIdentifier(j.RefColumn))
+	}
+
+	return q.buildSQL(b, fromB.String())
+}
+
+//sqlBuilder has the state of a sql query being built
+type sqlBuilder struct {
+	//dialect is the dialect in which the query is built
+	dialect Dialect
+	//tables are the tables in the query. If set the columns are qualified with the name of their table
+	tables map[string]TableNode
+	//args has the arguments bound to the query
+	args []interface{}
+	//diagnostics has the filters dropped while building the query
+	diagnostics []Diagnostic
+}
+
+//column returns the column identifier qualified with its table name if required
+func (b *sqlBuilder) column(c ColumnNode) string {
+	if b.tables == nil {
+		return b.dialect.QuoteIdentifier(c.Name)
+	}
+	t, ok := b.tables[c.PUID]
+	if !ok {
+		return b.dialect.QuoteIdentifier(c.Name)
+	}
+	return b.dialect.QuoteIdentifier(t.Name) + "." + b.dialect.QuoteIdentifier(c.Name)
+}
+
+//bind binds the value as argument to the query and returns its placeholder
+func (b *sqlBuilder) bind(value interface{}) string {
+	b.args = append(b.args, value)
+	return b.dialect.Placeholder(len(b.args))
+}
+
+//buildSQL builds the sql query with the given source of the data
+func (q Query) buildSQL(b *sqlBuilder, from string) (*SQLQuery, error) {
+	/*
+	 * We will iterate through the select fields
+	 * If group by fields are there, will add them to be selected
+	 * Then we will add the source
+	 * Then we will add the filters
+	 * Then we will add the group by if any
+	 * Then we will add the having filters if grouped
+	 * Then we will add the order by and limit if any
+	 */
 	var queryB strings.Builder
 	queryB.WriteString("SELECT ")
 	hasGroupBy := false
@@ -87,7 +213,7 @@ func (q Query) ToSingleTableSQL() (*SQLQuery, error) {
 		if len(v.Name) == 0 {
 			continue
 		}
-		addColumnString(count, v, &queryB, hasGroupBy)
+		addColumnString(count, v, &queryB, hasGroupBy, b)
 		count++
 	}
 
@@ -96,130 +222,397 @@ func (q Query) ToSingleTableSQL() (*SQLQuery, error) {
 		if len(v.Name) == 0 {
 			continue
 		}
-		addColumnString(count, v, &queryB, false)
+		addColumnString(count, v, &queryB, false, b)
 		count++
 	}
 
 	//adding the filters
-	queryB.WriteString(" FROM \"" + tableNode.Name + "\"")
+	queryB.WriteString(" FROM " + from)
+	b.writeFilters(&queryB, " WHERE ", q.Filters, q.FilterGroups, false)
+
+	//add the group by if required
+	if hasGroupBy {
+		count = 0
+		queryB.WriteString(" GROUP BY ")
+		for _, v := range q.GroupBy {
+			if len(v.Name) == 0 {
+				continue
+			}
+			if count != 0 {
+				queryB.WriteString(", ")
+			}
+			count++
+			queryB.WriteString(b.column(v))
+		}
+
+		//filters on the aggregated measures are applied after the grouping
+		b.writeFilters(&queryB, " HAVING ", q.Having, q.HavingGroups, true)
+	}
+
+	//add the order by if required
 	count = 0
-	values := []interface{}{}
-	done := false
-	index := 0
-	for _, v := range q.Filters {
-		if v.Column == nil || len(v.Column.Name) == 0 || (v.Value == nil && v.Unknown == nil && v.Time == nil) {
+	for _, v := range q.OrderBy {
+		if len(v.Column.Name) == 0 {
 			continue
 		}
-		if v.Unknown == nil && v.Time == nil && len(v.Value.Name) == 0 {
+		if count == 0 {
+			queryB.WriteString(" ORDER BY ")
+		} else {
+			queryB.WriteString(", ")
+		}
+		count++
+		//if the query is grouped, columns outside the group by has to be ordered with their aggregation
+		columnName := b.column(v.Column)
+		if hasGroupBy && !q.isGroupedBy(v.Column) {
+			columnName = b.aggregate(v.Column)
+		}
+		if v.Descending {
+			queryB.WriteString(columnName + " DESC")
+		} else {
+			queryB.WriteString(columnName + " ASC")
+		}
+	}
+
+	//add the limit if required
+	if q.Limit > 0 {
+		queryB.WriteString(" LIMIT " + strconv.Itoa(q.Limit))
+	}
+
+	result := &SQLQuery{Args: b.args, Query: queryB.String(), Diagnostics: b.diagnostics}
+	if result.Args == nil {
+		result.Args = []interface{}{}
+	}
+
+	return result, nil
+}
+
+//writeFilters writes the valid filters and filter groups joined with AND to the query with the given clause as prefix.
+//If aggregate is true, the filters are applied on the aggregation of their columns
+func (b *sqlBuilder) writeFilters(qS *strings.Builder, clause string, filters []OperatorNode, groups []FilterGroup, aggregate bool) {
+	conditions := []string{}
+	for _, v := range filters {
+		f, ok := b.filter(v, aggregate)
+		if !ok {
+			b.drop(v)
 			continue
 		}
-		if v.Value == nil && v.Time == nil && len(v.Unknown.Word) == 0 {
+		conditions = append(conditions, f)
+	}
+	for _, g := range groups {
+		f, ok := b.group(g, aggregate)
+		if !ok {
 			continue
 		}
-		if v.Value == nil && v.Unknown == nil && (!v.Time.Value.IsValid() || !v.Time.Value.From.IsValid()) {
+		conditions = append(conditions, f)
+	}
+	if len(conditions) == 0 {
+		return
+	}
+	qS.WriteString(clause + strings.Join(conditions, " AND "))
+}
+
+//group returns the condition for the filter group. Conditions of groups having more than one child are parenthesised.
+//If the group doesn't have any valid filters, it will return false
+func (b *sqlBuilder) group(g FilterGroup, aggregate bool) (string, bool) {
+	/*
+	 * We will get the conditions of the filters and the sub groups
+	 * Then we will combine them with the logic of the group
+	 */
+	conditions := []string{}
+	for _, v := range g.Filters {
+		f, ok := b.filter(v, aggregate)
+		if !ok {
+			b.drop(v)
 			continue
 		}
-		if (v.Column.DataType == DataTypeInt || v.Column.DataType == DataTypeFloat || v.Column.DataType == DataTypeDate) &&
-			(v.Operation != EqOperator && v.Operation != NotEqOperator && v.Operation != GreaterOperator && v.Operation != LessOperator) {
+		conditions = append(conditions, f)
+	}
+	for _, sg := range g.Groups {
+		f, ok := b.group(sg, aggregate)
+		if !ok {
 			continue
 		}
-		var convertedVal interface{}
-		if v.Value != nil {
-			vl, ok := getValue(v.Column.DataType, v.Value.Name)
-			if !ok {
-				continue
-			}
-			convertedVal = vl
-		} else if v.Unknown != nil {
-			vl, ok := getValue(v.Column.DataType, string(v.Unknown.Word))
-			if !ok {
-				continue
-			}
-			convertedVal = vl
-		} else if v.Time != nil {
-			if v.Column.DataType != DataTypeDate {
-				continue
+		conditions = append(conditions, f)
+	}
+	if len(conditions) == 0 {
+		return "", false
+	}
+
+	//combining the conditions
+	switch g.Logic {
+	case NotLogic:
+		return "NOT (" + strings.Join(conditions, " AND ") + ")", true
+	case OrLogic:
+		if len(conditions) == 1 {
+			return conditions[0], true
+		}
+		return "(" + strings.Join(conditions, " OR ") + ")", true
+	default:
+		if len(conditions) == 1 {
+			return conditions[0], true
+		}
+		return "(" + strings.Join(conditions, " AND ") + ")", true
+	}
+}
+
+//drop records the filter dropped from the query with the reason
+func (b *sqlBuilder) drop(v OperatorNode) {
+	f := v
+	b.diagnostics = append(b.diagnostics, Diagnostic{Kind: FilterDropped, Message: filterIssue(v), Filter: &f})
+}
+
+//filter returns the condition for the given filter with its arguments bound to the query.
+//If the filter is not valid, it will return false
+func (b *sqlBuilder) filter(v OperatorNode, aggregate bool) (string, bool) {
+	if v.Operation == InOperator {
+		return b.inFilter(v, aggregate)
+	}
+	if v.Column == nil || len(v.Column.Name) == 0 {
+		return "", false
+	}
+	if (v.Column.DataType == DataTypeInt || v.Column.DataType == DataTypeFloat || v.Column.DataType == DataTypeDate) &&
+		(v.Operation != EqOperator && v.Operation != NotEqOperator && v.Operation != GreaterOperator && v.Operation != LessOperator &&
+			v.Operation != BetweenOperator) {
+		return "", false
+	}
+	columnName := b.column(*v.Column)
+	if aggregate {
+		columnName = b.aggregate(*v.Column)
+	}
+
+	//time operands on the date columns are expanded to the range of their grain
+	if v.Time != nil && v.Value == nil && v.Unknown == nil && v.Number == nil && v.Column.DataType == DataTypeDate {
+		return b.timeFilter(columnName, v)
+	}
+
+	convertedVal, ok := b.operand(*v.Column, v.Value, v.Unknown, v.Number, v.Time)
+	if !ok {
+		return "", false
+	}
+
+	//between operator requires the upper bound of the range
+	if v.Operation == BetweenOperator {
+		toVal, ok := b.operand(*v.Column, v.ToValue, v.ToUnknown, v.ToNumber, v.ToTime)
+		if !ok {
+			return "", false
+		}
+		return columnName + " " + BetweenOperator + " " + b.bind(convertedVal) + " AND " + b.bind(toVal), true
+	}
+
+	//like and contains operators are pattern matches on the string columns
+	if (v.Operation == LikeOperator || v.Operation == ContainsOperator) && v.Column.DataType == DataTypeString {
+		return b.dialect.Like(columnName, b.bind("%"+convertedVal.(string)+"%")), true
+	}
+	return columnName + " " + v.Operation + " " + b.bind(convertedVal), true
+}
+
+//timeFilter returns the condition for the filter on a date column with time operands.
+//A time is expanded to the half open range [start, end) of its grain. So this month will match the whole month
+//instead of its first instant. The greater and less operators compare with the whole range like after and before in the datetime services.
+//So before 2019 is before the start of 2019 and after 2019 is from the end of 2019.
+//The open intervals like since january and till march are compared with their bounds. If the time is not valid, it will return false
+func (b *sqlBuilder) timeFilter(columnName string, v OperatorNode) (string, bool) {
+	/*
+	 * We will find the bounds of the time
+	 * For between operator the upper bound is taken from the end of the to time
+	 * Then based on the operator we will write the conditions
+	 */
+	if !v.Time.Value.IsValid() {
+		return "", false
+	}
+	from, to := v.Time.Value.Bounds()
+	if v.Operation == BetweenOperator {
+		if v.ToTime == nil || !v.ToTime.Value.IsValid() {
+			return "", false
+		}
+		toFrom, toTo := v.ToTime.Value.Bounds()
+		if toTo == nil {
+			//the to time doesn't have a grain, so the range includes the instant
+			if toFrom == nil || from == nil {
+				return "", false
 			}
-			convertedVal = v.Time.Value.From.Time
+			return "(" + columnName + " >= " + b.bind(b.dialect.DateValue(*from)) +
+				" AND " + columnName + " <= " + b.bind(b.dialect.DateValue(*toFrom)) + ")", true
 		}
+		to = toTo
+	}
 
-		if len(q.Filters) > 0 && !done {
-			queryB.WriteString(" WHERE ")
-			done = true
+	switch v.Operation {
+	case GreaterOperator:
+		if from != nil && to != nil {
+			return columnName + " >= " + b.bind(b.dialect.DateValue(*to)), true
 		}
-		if count != 0 {
-			queryB.WriteString(" AND ")
+		if from == nil {
+			return "", false
 		}
-		count++
-		columnName := "\"" + v.Column.Name + "\""
-		value := ""
-		if v.Column.DataType == DataTypeString {
-			value += "'"
+		return columnName + " >= " + b.bind(b.dialect.DateValue(*from)), true
+	case LessOperator:
+		if from != nil {
+			return columnName + " < " + b.bind(b.dialect.DateValue(*from)), true
+		}
+		if to == nil {
+			return "", false
 		}
-		if (v.Operation == LikeOperator || v.Operation == ContainsOperator) && v.Column.DataType == DataTypeString {
-			value += "%"
+		return columnName + " < " + b.bind(b.dialect.DateValue(*to)), true
+	case NotEqOperator:
+		if from == nil || to == nil {
+			return "", false
 		}
-		if v.Column.DataType == DataTypeString {
-			value += convertedVal.(string)
+		return "(" + columnName + " < " + b.bind(b.dialect.DateValue(*from)) +
+			" OR " + columnName + " >= " + b.bind(b.dialect.DateValue(*to)) + ")", true
+	case EqOperator, BetweenOperator:
+		if from != nil && to != nil {
+			return "(" + columnName + " >= " + b.bind(b.dialect.DateValue(*from)) +
+				" AND " + columnName + " < " + b.bind(b.dialect.DateValue(*to)) + ")", true
 		}
-		if (v.Operation == LikeOperator || v.Operation == ContainsOperator) && v.Column.DataType == DataTypeString {
-			value += "%"
+		if from != nil && v.Time.Value.Type == "value" {
+			//the time doesn't have a known grain
+			return columnName + " = " + b.bind(b.dialect.DateValue(*from)), true
 		}
-		if v.Column.DataType == DataTypeString {
-			value += "'"
+		if from != nil {
+			return columnName + " >= " + b.bind(b.dialect.DateValue(*from)), true
 		}
-		var finalValue interface{} = value
-		if v.Column.DataType != DataTypeString {
-			finalValue = convertedVal
+		if to != nil {
+			return columnName + " < " + b.bind(b.dialect.DateValue(*to)), true
 		}
-		index++
-		queryB.WriteString(columnName + " " + v.Operation + " $" + strconv.Itoa(index))
-		values = append(values, finalValue)
 	}
+	return "", false
+}
 
-	//add the group by if required
-	result := &SQLQuery{Args: values}
-	if !hasGroupBy {
-		result.Query = queryB.String()
-		return result, nil
+//operand returns the argument to be bound for an operand of a filter on the given column.
+//The first available among value, unknown, number and time is taken as the operand. If the operand is not valid, it will return false
+func (b *sqlBuilder) operand(column ColumnNode, value *ValueNode, unknown *UnknownNode, number *NumberNode, t *TimeNode) (interface{}, bool) {
+	if value != nil {
+		if len(value.Name) == 0 {
+			return nil, false
+		}
+		return b.value(column.DataType, value.Name)
 	}
-	count = 0
-	queryB.WriteString(" GROUP BY ")
-	for _, v := range q.GroupBy {
-		if len(v.Name) == 0 {
-			continue
+	if unknown != nil {
+		//literals are bound exactly as they were quoted
+		if unknown.Literal {
+			return b.value(column.DataType, string(unknown.Word))
 		}
-		if count != 0 {
-			queryB.WriteString(", ")
+		word := strings.TrimSpace(string(unknown.Word))
+		if len(word) == 0 {
+			return nil, false
 		}
-		count++
-		queryB.WriteString("\"" + v.Name + "\"")
+		return b.value(column.DataType, word)
+	}
+	if number != nil {
+		return number.Arg(column.DataType)
 	}
-	result.Query = queryB.String()
+	if t != nil {
+		if column.DataType != DataTypeDate || !t.Value.IsValid() || t.Value.From == nil || !t.Value.From.IsValid() {
+			return nil, false
+		}
+		return b.dialect.DateValue(*t.Value.From.Time), true
+	}
+	return nil, false
+}
 
-	return result, nil
+//value converts the given string to the argument to be bound for the data type
+func (b *sqlBuilder) value(dataType string, value string) (interface{}, bool) {
+	vl, ok := getValue(dataType, value)
+	if !ok {
+		return nil, false
+	}
+	if t, isTime := vl.(time.Time); isTime {
+		return b.dialect.DateValue(t), true
+	}
+	return vl, true
+}
+
+//inFilter returns the condition for the filter with in operator
+func (b *sqlBuilder) inFilter(v OperatorNode, aggregate bool) (string, bool) {
+	if v.Column == nil || len(v.Column.Name) == 0 || len(v.Values) == 0 {
+		return "", false
+	}
+	values := []interface{}{}
+	for _, vl := range v.Values {
+		if len(vl.Name) == 0 {
+			return "", false
+		}
+		convertedVal, ok := b.value(v.Column.DataType, vl.Name)
+		if !ok {
+			return "", false
+		}
+		values = append(values, convertedVal)
+	}
+	columnName := b.column(*v.Column)
+	if aggregate {
+		columnName = b.aggregate(*v.Column)
+	}
+	placeholders := []string{}
+	for _, vl := range values {
+		placeholders = append(placeholders, b.bind(vl))
+	}
+	return columnName + " " + InOperator + " (" + strings.Join(placeholders, ", ") + ")", true
+}
+
+//isGroupedBy returns true if the column is used for grouping in the query
+func (q Query) isGroupedBy(c ColumnNode) bool {
+	for _, v := range q.GroupBy {
+		if v.Name == c.Name && v.PUID == c.PUID {
+			return true
+		}
+	}
+	return false
+}
+
+//aggregate returns the column with its aggregation function applied
+func (b *sqlBuilder) aggregate(v ColumnNode) string {
+	fn := v.AggregationFn
+	if len(fn) == 0 {
+		fn = DefaultAggregationFn
+	}
+	return b.dialect.AggregationFn(fn) + "(" + b.column(v) + ")"
 }
 
-func addColumnString(i int, v ColumnNode, qS *strings.Builder, enforceGroupBy bool) {
+//addColumnString adds the column to the selected fields of the query.
+//Aggregated columns and the columns qualified with their table names are aliased with the name of the column
+func addColumnString(i int, v ColumnNode, qS *strings.Builder, enforceGroupBy bool, b *sqlBuilder) {
 	if i != 0 {
 		qS.WriteString(", ")
 	}
-	columnName := "\"" + v.Name + "\""
-	if enforceGroupBy && len(v.AggregationFn) != 0 {
-		columnName = v.AggregationFn + "(" + columnName + ") AS " + columnName
-	} else if enforceGroupBy && len(v.AggregationFn) == 0 {
-		columnName = DefaultAggregationFn + "(" + columnName + ") AS " + columnName
+	columnName := b.column(v)
+	if enforceGroupBy {
+		columnName = b.aggregate(v)
 	}
-	qS.WriteString(columnName + " ")
+	if !enforceGroupBy && b.tables == nil {
+		qS.WriteString(columnName)
+		return
+	}
+	qS.WriteString(columnName + " AS " + b.dialect.QuoteIdentifier(v.Name))
+}
+
+//dateLayouts are the layouts with which the date values in a query are parsed
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
 }
 
 func getValue(dataType string, value string) (interface{}, bool) {
-	if dataType == DataTypeDate || dataType == DataTypeString {
+	if dataType == DataTypeString {
+		return value, true
+	}
+	if dataType == DataTypeDate {
+		//if the date couldn't be parsed it is passed on as it is
+		for _, layout := range dateLayouts {
+			if t, err := time.Parse(layout, value); err == nil {
+				return t, true
+			}
+		}
 		return value, true
 	}
 	if dataType == DataTypeInt {
 		val, err := strconv.ParseInt(value, 10, 64)
 		if err != nil {
+			//numbers like 1,000 and 2 million are parsed as number nodes
+			if n, ok := ParseNumber(value); ok {
+				return n.Arg(dataType)
+			}
 			return nil, false
 		}
 		return val, true
@@ -227,6 +620,9 @@ func getValue(dataType string, value string) (interface{}, bool) {
 	if dataType == DataTypeFloat {
 		val, err := strconv.ParseFloat(value, 64)
 		if err != nil {
+			if n, ok := ParseNumber(value); ok {
+				return n.Arg(dataType)
+			}
 			return nil, false
 		}
 		return val, true
@@ -5,9 +5,12 @@
 package interpreter_test
 
 import (
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/cuttle-ai/octopus/datetime"
 	"github.com/cuttle-ai/octopus/interpreter"
 )
 
@@ -37,7 +40,8 @@ var testQueries = []query{
 				"1": testTable,
 			},
 		},
-		s: `SELECT "city" AS "city" FROM "stores"`,
+		//the columns of a single table are aliased only when aggregated as they already have the name of the column
+		s: `SELECT "city" FROM "stores"`,
 		n: "normal select",
 	},
 	{
@@ -56,9 +60,110 @@ var testQueries = []query{
 				},
 			},
 		},
-		s: `SELECT "city" AS "city" FROM "stores" WHERE "city" = ?`,
+		//the default dialect is postgres which binds the args with numbered placeholders
+		s: `SELECT "city" FROM "stores" WHERE "city" = $1`,
 		n: "normal select with filters",
 	},
+	{
+		q: interpreter.Query{
+			Select: []interpreter.ColumnNode{
+				testColumnCity,
+			},
+			Tables: map[string]interpreter.TableNode{
+				"1": testTable,
+			},
+			OrderBy: []interpreter.Ordering{
+				{Column: testColumnCity},
+			},
+			Limit: 10,
+		},
+		s: `SELECT "city" FROM "stores" ORDER BY "city" ASC LIMIT 10`,
+		n: "select with order by and limit",
+	},
+	{
+		q: interpreter.Query{
+			Select: []interpreter.ColumnNode{
+				{Name: "sales", DataType: interpreter.DataTypeInt, Measure: true, AggregationFn: interpreter.AggregationFnSum},
+			},
+			GroupBy: []interpreter.ColumnNode{
+				testColumnCity,
+			},
+			Tables: map[string]interpreter.TableNode{
+				"1": testTable,
+			},
+			OrderBy: []interpreter.Ordering{
+				{Column: interpreter.ColumnNode{Name: "sales", DataType: interpreter.DataTypeInt, Measure: true, AggregationFn: interpreter.AggregationFnSum}, Descending: true},
+			},
+			Limit: 5,
+		},
+		s: `SELECT SUM("sales") AS "sales", "city" FROM "stores" GROUP BY "city" ORDER BY SUM("sales") DESC LIMIT 5`,
+		n: "top n with group by",
+	},
+	{
+		q: interpreter.Query{
+			Select: []interpreter.ColumnNode{
+				{Name: "sales", DataType: interpreter.DataTypeInt, Measure: true, AggregationFn: interpreter.AggregationFnSum},
+			},
+			GroupBy: []interpreter.ColumnNode{
+				testColumnCity,
+			},
+			Tables: map[string]interpreter.TableNode{
+				"1": testTable,
+			},
+			Filters: []interpreter.OperatorNode{
+				{
+					Operation: interpreter.EqOperator,
+					Column:    &testColumnCity,
+					Unknown:   &testUnknownCity,
+				},
+			},
+			Having: []interpreter.OperatorNode{
+				{
+					Operation: interpreter.GreaterOperator,
+					Column:    &interpreter.ColumnNode{Name: "sales", DataType: interpreter.DataTypeInt, Measure: true, AggregationFn: interpreter.AggregationFnSum},
+					Unknown:   &interpreter.UnknownNode{Word: []rune(" 1000")},
+				},
+			},
+		},
+		s: `SELECT SUM("sales") AS "sales", "city" FROM "stores" WHERE "city" = $1 GROUP BY "city" HAVING SUM("sales") >= $2`,
+		n: "having filter on measure",
+	},
+	{
+		q: interpreter.Query{
+			Select: []interpreter.ColumnNode{
+				testColumnCity,
+			},
+			Tables: map[string]interpreter.TableNode{
+				"1": testTable,
+			},
+			Filters: []interpreter.OperatorNode{
+				{
+					Operation: interpreter.InOperator,
+					Column:    &testColumnCity,
+					Values:    []interpreter.ValueNode{{Name: "Delhi"}, {Name: "Mumbai"}},
+				},
+			},
+			FilterGroups: []interpreter.FilterGroup{
+				{
+					Logic: interpreter.OrLogic,
+					Filters: []interpreter.OperatorNode{
+						{Operation: interpreter.EqOperator, Column: &testColumnCity, Unknown: &interpreter.UnknownNode{Word: []rune("Pune")}},
+					},
+					Groups: []interpreter.FilterGroup{
+						{
+							Logic: interpreter.NotLogic,
+							Filters: []interpreter.OperatorNode{
+								{Operation: interpreter.InOperator, Column: &testColumnCity, Values: []interpreter.ValueNode{{Name: "Goa"}}},
+								{Operation: interpreter.NotEqOperator, Column: &testColumnCity, Unknown: &interpreter.UnknownNode{Word: []rune("Agra")}},
+							},
+						},
+					},
+				},
+			},
+		},
+		s: `SELECT "city" FROM "stores" WHERE "city" IN ($1, $2) AND ("city" = $3 OR NOT ("city" IN ($4) AND "city" <> $5))`,
+		n: "in filter with filter groups",
+	},
 }
 
 func TestToSQL(t *testing.T) {
@@ -75,3 +180,351 @@ func TestToSQL(t *testing.T) {
 		})
 	}
 }
+
+var testFactTable = interpreter.TableNode{
+	UID:  "sales",
+	Name: "sales",
+	ForeignKeys: []interpreter.ForeignKey{
+		{Column: "brand_id", RefTableUID: "brands", RefColumn: "id"},
+		{Column: "store_id", RefTableUID: "stores", RefColumn: "id"},
+	},
+}
+
+var testBrandTable = interpreter.TableNode{UID: "brands", Name: "brands"}
+
+var testStoreTable = interpreter.TableNode{UID: "stores", Name: "stores"}
+
+var testColumnAmount = interpreter.ColumnNode{Name: "amount", PUID: "sales", DataType: interpreter.DataTypeInt, Measure: true}
+
+var testColumnBrand = interpreter.ColumnNode{Name: "name", PUID: "brands", DataType: interpreter.DataTypeString, Dimension: true}
+
+var testColumnStoreCity = interpreter.ColumnNode{Name: "city", PUID: "stores", DataType: interpreter.DataTypeString}
+
+var testJoinQueries = []query{
+	{
+		q: interpreter.Query{
+			Select:  []interpreter.ColumnNode{testColumnAmount},
+			GroupBy: []interpreter.ColumnNode{testColumnBrand},
+			Tables: map[string]interpreter.TableNode{
+				"sales":  testFactTable,
+				"brands": testBrandTable,
+			},
+		},
+		s: `SELECT COUNT("sales"."amount") AS "amount", "brands"."name" AS "name" FROM "sales" JOIN "brands" ON "brands"."id" = "sales"."brand_id" GROUP BY "brands"."name"`,
+		n: "fact and dimension join",
+	},
+	{
+		q: interpreter.Query{
+			Select: []interpreter.ColumnNode{testColumnBrand},
+			Tables: map[string]interpreter.TableNode{
+				"sales":  testFactTable,
+				"brands": testBrandTable,
+				"stores": testStoreTable,
+			},
+			Filters: []interpreter.OperatorNode{
+				{
+					Operation: interpreter.EqOperator,
+					Column:    &testColumnStoreCity,
+					Unknown:   &testUnknownCity,
+				},
+			},
+		},
+		s: `SELECT "brands"."name" AS "name" FROM "sales" JOIN "brands" ON "brands"."id" = "sales"."brand_id" JOIN "stores" ON "stores"."id" = "sales"."store_id" WHERE "stores"."city" = $1`,
+		n: "join with multiple dimensions and filter",
+	},
+}
+
+func TestToSQLJoins(t *testing.T) {
+	for _, v := range testJoinQueries {
+		t.Run(v.n, func(t *testing.T) {
+			s, err := v.q.ToSQL()
+			if err != nil {
+				t.Error("error while running the test", err)
+				return
+			}
+			if strings.Compare(s.Query, v.s) != 0 {
+				t.Error("Expected query", "`"+v.s+"`", "got", "`"+s.Query+"`")
+			}
+		})
+	}
+}
+
+var testJoinErrorQueries = []query{
+	{
+		q: interpreter.Query{
+			Select: []interpreter.ColumnNode{testColumnBrand, testColumnStoreCity},
+			Tables: map[string]interpreter.TableNode{
+				"brands": testBrandTable,
+				"stores": testStoreTable,
+			},
+		},
+		n: "no join path",
+	},
+	{
+		q: interpreter.Query{
+			Select: []interpreter.ColumnNode{testColumnAmount, testColumnBrand},
+			Tables: map[string]interpreter.TableNode{
+				"sales": {
+					UID:  "sales",
+					Name: "sales",
+					ForeignKeys: []interpreter.ForeignKey{
+						{Column: "brand_id", RefTableUID: "brands", RefColumn: "id"},
+						{Column: "parent_brand_id", RefTableUID: "brands", RefColumn: "id"},
+					},
+				},
+				"brands": testBrandTable,
+			},
+		},
+		n: "ambiguous join path",
+	},
+	{
+		q: interpreter.Query{
+			Select: []interpreter.ColumnNode{testColumnAmount, testColumnBrand, testColumnStoreCity},
+			Tables: map[string]interpreter.TableNode{
+				"sales": {
+					UID:  "sales",
+					Name: "sales",
+					ForeignKeys: []interpreter.ForeignKey{
+						{Column: "brand_id", RefTableUID: "brands", RefColumn: "id"},
+						{Column: "parent_brand_id", RefTableUID: "brands", RefColumn: "id"},
+						{Column: "store_id", RefTableUID: "stores", RefColumn: "id"},
+						{Column: "warehouse_id", RefTableUID: "stores", RefColumn: "id"},
+					},
+				},
+				"brands": testBrandTable,
+				"stores": testStoreTable,
+			},
+		},
+		s: "ambiguous join path between the tables brands and sales. Found 2 relationships between them",
+		n: "ambiguous join paths reported in order",
+	},
+}
+
+func TestToSQLJoinErrors(t *testing.T) {
+	for _, v := range testJoinErrorQueries {
+		t.Run(v.n, func(t *testing.T) {
+			//the error is checked across the runs as the tables are kept in a map
+			for i := 0; i < 10; i++ {
+				_, err := v.q.ToSQL()
+				if err == nil {
+					t.Fatal("Expected an error while converting the query to sql. Got none")
+				}
+				if len(v.s) != 0 && err.Error() != v.s {
+					t.Fatal("Expected the error", v.s, "got", err)
+				}
+			}
+		})
+	}
+}
+
+type timeGrainQuery struct {
+	n     string
+	value datetime.Value
+	op    string
+	s     string
+	args  []interface{}
+}
+
+var testTimeGrainQueries = []timeGrainQuery{
+	{
+		n:     "day",
+		value: datetime.Value{Type: "value", Value: "2019-05-21T00:00:00Z", Gran: datetime.GrainDay},
+		op:    interpreter.EqOperator,
+		s:     `SELECT "city" FROM "stores" WHERE ("order date" >= $1 AND "order date" < $2)`,
+		args:  []interface{}{time.Date(2019, time.May, 21, 0, 0, 0, 0, time.UTC), time.Date(2019, time.May, 22, 0, 0, 0, 0, time.UTC)},
+	},
+	{
+		n:     "week",
+		value: datetime.Value{Type: "value", Value: "2019-05-20T00:00:00Z", Gran: datetime.GrainWeek},
+		op:    interpreter.EqOperator,
+		s:     `SELECT "city" FROM "stores" WHERE ("order date" >= $1 AND "order date" < $2)`,
+		args:  []interface{}{time.Date(2019, time.May, 20, 0, 0, 0, 0, time.UTC), time.Date(2019, time.May, 27, 0, 0, 0, 0, time.UTC)},
+	},
+	{
+		n:     "month",
+		value: datetime.Value{Type: "value", Value: "2019-05-01T00:00:00Z", Gran: datetime.GrainMonth},
+		op:    interpreter.EqOperator,
+		s:     `SELECT "city" FROM "stores" WHERE ("order date" >= $1 AND "order date" < $2)`,
+		args:  []interface{}{time.Date(2019, time.May, 1, 0, 0, 0, 0, time.UTC), time.Date(2019, time.June, 1, 0, 0, 0, 0, time.UTC)},
+	},
+	{
+		n:     "quarter",
+		value: datetime.Value{Type: "value", Value: "2019-04-01T00:00:00Z", Gran: datetime.GrainQuarter},
+		op:    interpreter.EqOperator,
+		s:     `SELECT "city" FROM "stores" WHERE ("order date" >= $1 AND "order date" < $2)`,
+		args:  []interface{}{time.Date(2019, time.April, 1, 0, 0, 0, 0, time.UTC), time.Date(2019, time.July, 1, 0, 0, 0, 0, time.UTC)},
+	},
+	{
+		n:     "year",
+		value: datetime.Value{Type: "value", Value: "2019-01-01T00:00:00Z", Gran: datetime.GrainYear},
+		op:    interpreter.EqOperator,
+		s:     `SELECT "city" FROM "stores" WHERE ("order date" >= $1 AND "order date" < $2)`,
+		args:  []interface{}{time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	},
+	{
+		n:     "not in month",
+		value: datetime.Value{Type: "value", Value: "2019-05-01T00:00:00Z", Gran: datetime.GrainMonth},
+		op:    interpreter.NotEqOperator,
+		s:     `SELECT "city" FROM "stores" WHERE ("order date" < $1 OR "order date" >= $2)`,
+		args:  []interface{}{time.Date(2019, time.May, 1, 0, 0, 0, 0, time.UTC), time.Date(2019, time.June, 1, 0, 0, 0, 0, time.UTC)},
+	},
+	{
+		n:     "before month",
+		value: datetime.Value{Type: "value", Value: "2019-05-01T00:00:00Z", Gran: datetime.GrainMonth},
+		op:    interpreter.LessOperator,
+		s:     `SELECT "city" FROM "stores" WHERE "order date" < $1`,
+		args:  []interface{}{time.Date(2019, time.May, 1, 0, 0, 0, 0, time.UTC)},
+	},
+	{
+		n:     "before year",
+		value: datetime.Value{Type: "value", Value: "2019-01-01T00:00:00Z", Gran: datetime.GrainYear},
+		op:    interpreter.LessOperator,
+		s:     `SELECT "city" FROM "stores" WHERE "order date" < $1`,
+		args:  []interface{}{time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	},
+	{
+		n:     "after year",
+		value: datetime.Value{Type: "value", Value: "2019-01-01T00:00:00Z", Gran: datetime.GrainYear},
+		op:    interpreter.GreaterOperator,
+		s:     `SELECT "city" FROM "stores" WHERE "order date" >= $1`,
+		args:  []interface{}{time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	},
+	{
+		n: "till month",
+		value: datetime.Value{
+			Type: "interval",
+			To:   &datetime.TimeValue{Value: "2019-06-01T00:00:00Z", Gran: datetime.GrainMonth},
+		},
+		op:   interpreter.LessOperator,
+		s:    `SELECT "city" FROM "stores" WHERE "order date" < $1`,
+		args: []interface{}{time.Date(2019, time.June, 1, 0, 0, 0, 0, time.UTC)},
+	},
+	{
+		n: "since month",
+		value: datetime.Value{
+			Type: "interval",
+			From: &datetime.TimeValue{Value: "2019-01-01T00:00:00Z", Gran: datetime.GrainMonth},
+		},
+		op:   interpreter.GreaterOperator,
+		s:    `SELECT "city" FROM "stores" WHERE "order date" >= $1`,
+		args: []interface{}{time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	},
+	{
+		n: "interval",
+		value: datetime.Value{
+			Type: "interval",
+			From: &datetime.TimeValue{Value: "2019-05-01T00:00:00Z", Gran: datetime.GrainDay},
+			To:   &datetime.TimeValue{Value: "2019-05-08T00:00:00Z", Gran: datetime.GrainDay},
+		},
+		op:   interpreter.EqOperator,
+		s:    `SELECT "city" FROM "stores" WHERE ("order date" >= $1 AND "order date" < $2)`,
+		args: []interface{}{time.Date(2019, time.May, 1, 0, 0, 0, 0, time.UTC), time.Date(2019, time.May, 8, 0, 0, 0, 0, time.UTC)},
+	},
+}
+
+func TestToSQLTimeGrains(t *testing.T) {
+	for _, v := range testTimeGrainQueries {
+		t.Run(v.n, func(t *testing.T) {
+			column := interpreter.ColumnNode{Name: "order date", DataType: interpreter.DataTypeDate}
+			q := interpreter.Query{
+				Select: []interpreter.ColumnNode{testColumnCity},
+				Tables: map[string]interpreter.TableNode{"1": testTable},
+				Filters: []interpreter.OperatorNode{
+					{Operation: v.op, Column: &column, Time: &interpreter.TimeNode{Value: v.value}},
+				},
+			}
+			s, err := q.ToSQL()
+			if err != nil {
+				t.Error("error while running the test", err)
+				return
+			}
+			if strings.Compare(s.Query, v.s) != 0 {
+				t.Error("Expected query", "`"+v.s+"`", "got", "`"+s.Query+"`")
+			}
+			if len(s.Args) != len(v.args) {
+				t.Fatal("Expected args", v.args, "got", s.Args)
+			}
+			for i := range v.args {
+				if !reflect.DeepEqual(s.Args[i].(time.Time).UTC(), v.args[i]) {
+					t.Error("Expected arg", v.args[i], "got", s.Args[i])
+				}
+			}
+		})
+	}
+}
+
+var testUnknownAmount = interpreter.UnknownNode{Word: []rune("1000")}
+
+var testMeasureFilterQueries = []query{
+	{
+		q: interpreter.Query{
+			Select:  []interpreter.ColumnNode{testColumnAmount},
+			GroupBy: []interpreter.ColumnNode{testColumnBrand},
+			Tables:  map[string]interpreter.TableNode{"1": testTable},
+			Filters: []interpreter.OperatorNode{
+				{Operation: interpreter.GreaterOperator, Column: &testColumnAmount, Unknown: &testUnknownAmount},
+				{Operation: interpreter.EqOperator, Column: &testColumnCity, Unknown: &testUnknownCity},
+			},
+		},
+		s: `SELECT COUNT("amount") AS "amount", "name" FROM "stores" WHERE "city" = $1 GROUP BY "name" HAVING SUM("amount") >= $2`,
+		n: "grouped query",
+	},
+	{
+		q: interpreter.Query{
+			Select: []interpreter.ColumnNode{testColumnBrand},
+			Tables: map[string]interpreter.TableNode{"1": testTable},
+			Filters: []interpreter.OperatorNode{
+				{Operation: interpreter.GreaterOperator, Column: &testColumnAmount, Unknown: &testUnknownAmount},
+			},
+		},
+		s: `SELECT "name" FROM "stores" GROUP BY "name" HAVING SUM("amount") >= $1`,
+		n: "dimension borrowed from the group by",
+	},
+	{
+		q: interpreter.Query{
+			Select: []interpreter.ColumnNode{testColumnAmount},
+			Tables: map[string]interpreter.TableNode{"1": testTable},
+			Filters: []interpreter.OperatorNode{
+				{Operation: interpreter.GreaterOperator, Column: &testColumnAmount, Unknown: &testUnknownAmount},
+			},
+		},
+		s: `SELECT "amount" FROM "stores" WHERE "amount" >= $1`,
+		n: "query without grouping",
+	},
+	{
+		q: interpreter.Query{
+			Select: []interpreter.ColumnNode{testColumnBrand},
+			Tables: map[string]interpreter.TableNode{"1": testTable},
+			FilterGroups: []interpreter.FilterGroup{
+				{Logic: interpreter.OrLogic, Filters: []interpreter.OperatorNode{
+					{Operation: interpreter.GreaterOperator, Column: &testColumnAmount, Unknown: &testUnknownAmount},
+					{Operation: interpreter.LessOperator, Column: &testColumnAmount, Unknown: &testUnknownAmount},
+				}},
+				{Logic: interpreter.OrLogic, Filters: []interpreter.OperatorNode{
+					{Operation: interpreter.GreaterOperator, Column: &testColumnAmount, Unknown: &testUnknownAmount},
+					{Operation: interpreter.EqOperator, Column: &testColumnCity, Unknown: &testUnknownCity},
+				}},
+			},
+		},
+		s: `SELECT "name" FROM "stores" WHERE ("amount" >= $1 OR "city" = $2) GROUP BY "name" HAVING (SUM("amount") >= $3 OR SUM("amount") <= $4)`,
+		n: "filter groups on the measures",
+	},
+}
+
+func TestRouteMeasureFilters(t *testing.T) {
+	for _, v := range testMeasureFilterQueries {
+		t.Run(v.n, func(t *testing.T) {
+			v.q.RouteMeasureFilters()
+			s, err := v.q.ToSQL()
+			if err != nil {
+				t.Fatal("error while running the test", err)
+			}
+			if strings.Compare(s.Query, v.s) != 0 {
+				t.Error("Expected query", "`"+v.s+"`", "got", "`"+s.Query+"`")
+			}
+			if len(testColumnAmount.AggregationFn) != 0 {
+				t.Error("Expected the column of the filter not to be mutated")
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package interpreter
+
+import "encoding/json"
+
+/*
+ * This file contains the defnition of rank type node
+ */
+
+//RankNode is the node storing the information about a superlative/ranking word like top, highest, lowest.
+//The results of the query are sorted and limited based on this node
+type RankNode struct {
+	//UID is the unique id of the rank node
+	UID string
+	//Word is the word with which the rank node has to be matched
+	Word []rune
+	//PUID is the UID of rank's parent node
+	PUID string
+	//PN is the parent node of the rank. It will be a KnowledgeBase
+	PN Node
+	//Resolved indicates that the node is resolved
+	Resolved bool
+	//Descending indicates that the results has to be sorted in the descending order. Like for top, highest etc
+	Descending bool
+	//Limit is the default no. of results implied by the word. Like 1 for highest. 0 indicates no default limit
+	Limit int
+}
+
+type rankNode struct {
+	UID        string `json:"uid,omitempty"`
+	Word       string `json:"word,omitempty"`
+	PUID       string `json:"puid,omitempty"`
+	Resolved   bool   `json:"resolved,omitempty"`
+	Type       string `json:"type,omitempty"`
+	Descending bool   `json:"descending,omitempty"`
+	Limit      int    `json:"limit,omitempty"`
+}
+
+//Copy will return a copy of the node
+func (r *RankNode) Copy() Node {
+	return &RankNode{
+		UID:        r.UID,
+		Word:       r.Word,
+		PN:         r.PN,
+		PUID:       r.PUID,
+		Resolved:   r.Resolved,
+		Descending: r.Descending,
+		Limit:      r.Limit,
+	}
+}
+
+//ID returns the unique id of the node
+func (r *RankNode) ID() string {
+	return r.UID
+}
+
+//Type returns Rank Type
+func (r *RankNode) Type() Type {
+	return Rank
+}
+
+//TokenWord returns the word property of the node
+func (r *RankNode) TokenWord() []rune {
+	return r.Word
+}
+
+//PID returns the PUID if the node
+func (r *RankNode) PID() string {
+	return r.PUID
+}
+
+//Parent returns the PN of the node
+func (r *RankNode) Parent() Node {
+	return r.PN
+}
+
+//MarshalJSON encodes the node into a serializable json
+func (r *RankNode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&rankNode{
+		r.UID, string(r.Word), r.PUID, r.Resolved, "Rank", r.Descending, r.Limit,
+	})
+}
+
+//UnmarshalJSON decodes the node from a json
+func (r *RankNode) UnmarshalJSON(data []byte) error {
+	m := &rankNode{}
+	err := json.Unmarshal(data, m)
+	if err != nil {
+		return err
+	}
+	r.UID = m.UID
+	r.Word = []rune(m.Word)
+	r.PUID = m.PUID
+	r.Resolved = m.Resolved
+	r.Descending = m.Descending
+	r.Limit = m.Limit
+	return nil
+}
+
+//IsResolved will return true if the node is resolved
+func (r *RankNode) IsResolved() bool {
+	return r.Resolved
+}
+
+//SetResolved will set the resolved state of the node
+func (r *RankNode) SetResolved(state bool) {
+	r.Resolved = state
+}
@@ -28,6 +28,8 @@ type Rule struct {
 	Matches []int `json:"-"`
 	//Pattern is the kmp buildup of the pattern
 	Pattern *KMP `json:"-"`
+	//Order is the position of the matched rule in the order in which the rules are run in the interpreter
+	Order int `json:"-"`
 }
 
 //RuleGroup stores the list of rules to be executed together with priority
@@ -87,18 +89,22 @@ func MatchRules(tokens []FastToken) []Rule {
 	tokPattern := BuildPattern(tokens)
 
 	//trying to find matches for the rules with the token pattern
+	order := 0
 	for _, gr := range rules {
+		if gr == nil {
+			continue
+		}
 		for _, r := range gr.Rules {
 			//if the disabled skip the rule
-			if r.Disabled {
+			if r.Disabled || r.Resolve == nil {
 				continue
 			}
 			pos := r.Pattern.Matches(tokPattern)
 			if len(pos) > 0 {
-				newRule := Rule{Name: r.Name, Matches: pos, Resolve: r.Resolve}
-				copy(newRule.Template, r.Template)
+				newRule := Rule{Name: r.Name, Description: r.Description, Template: append([]Type{}, r.Template...), Matches: pos, Resolve: r.Resolve, Order: order}
 				result = append(result, newRule)
 			}
+			order++
 		}
 	}
 	return result
@@ -130,6 +136,9 @@ func BuildPattern(tokens []FastToken) []Type {
 		//We will only take the first node in the token in the following order
 		// Operator
 		// Value
+		// Time
+		// Rank
+		// Number
 		// Column
 		// Table
 		// Unknown
@@ -139,6 +148,10 @@ func BuildPattern(tokens []FastToken) []Type {
 			result = append(result, Value)
 		} else if len(v.Times) > 0 {
 			result = append(result, Time)
+		} else if len(v.Ranks) > 0 {
+			result = append(result, Rank)
+		} else if len(v.Numbers) > 0 {
+			result = append(result, Number)
 		} else if len(v.Columns) > 0 {
 			result = append(result, Column)
 		} else if len(v.Tables) > 0 {
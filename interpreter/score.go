@@ -0,0 +1,112 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package interpreter
+
+import "strings"
+
+/*
+ * This file contains the utilities for scoring the interpretations of the tokens
+ */
+
+//Interpretation is an interpretation of the tokens along with its score
+type Interpretation struct {
+	//Query is the query interpreted from the tokens
+	Query Query `json:"query"`
+	//Score is the confidence of the interpretation from 0 to 1
+	Score float64 `json:"score"`
+}
+
+//ScoreWeights are the weights of the factors in the score of an interpretation
+type ScoreWeights struct {
+	//Resolved is the weight of the fraction of the tokens other than unknowns resolved by the rules
+	Resolved float64
+	//Rules is the weight of the priority of the rules applied. Rules run earlier have higher priority
+	Rules float64
+	//Unknowns is the weight of the fraction of the tokens not left as unresolved unknowns
+	Unknowns float64
+	//Fuzzy is the penalty of the fuzzy matched tokens resolved. Each of them is penalised by its uncertainty relative to the no. of tokens
+	Fuzzy float64
+}
+
+//DefaultScoreWeights are the default weights used for scoring the interpretations
+var DefaultScoreWeights = ScoreWeights{Resolved: 0.6, Rules: 0.2, Unknowns: 0.2, Fuzzy: 0.5}
+
+//resolvedTokens returns the no. of tokens resolved
+func resolvedTokens(toks []FastToken) int {
+	result := 0
+	for _, tok := range toks {
+		if tok.resolved() {
+			result++
+		}
+	}
+	return result
+}
+
+//enabledRules returns the no. of rules enabled in the interpreter
+func enabledRules() int {
+	result := 0
+	for _, gr := range rules {
+		if gr == nil {
+			continue
+		}
+		for _, r := range gr.Rules {
+			if !r.Disabled && r.Resolve != nil {
+				result++
+			}
+		}
+	}
+	return result
+}
+
+//score returns the score of the interpretation of the tokens with the rules applied
+func score(toks []FastToken, applied []Rule, w ScoreWeights) float64 {
+	/*
+	 * We will find the fraction of the known tokens resolved, the unresolved unknowns and the penalty of the fuzzy matches
+	 * Then we will find the priority of the rules applied
+	 * Then the factors are weighed
+	 */
+	if len(toks) == 0 {
+		return 0
+	}
+	known, resolved, unknowns, fuzzy := 0, 0, 0, 0.0
+	for _, tok := range toks {
+		isUnknown := len(tok.Unknowns) > 0 && len(tok.Unknowns)+len(tok.Operators)+len(tok.Values)+len(tok.Times)+
+			len(tok.Ranks)+len(tok.Numbers)+len(tok.Columns)+len(tok.Tables) == len(tok.Unknowns)
+		switch {
+		case isUnknown && !tok.resolved() && len(strings.TrimSpace(string(tok.Word))) > 0:
+			unknowns++
+		case !isUnknown:
+			known++
+			if tok.resolved() {
+				resolved++
+				if tok.Fuzzy != nil {
+					fuzzy += 1 - tok.Fuzzy.Confidence
+				}
+			}
+		}
+	}
+
+	//priority of the rules
+	rulesScore := 0.0
+	if total := enabledRules(); total > 0 && len(applied) > 0 {
+		for _, r := range applied {
+			rulesScore += 1 - float64(r.Order)/float64(total)
+		}
+		rulesScore /= float64(len(applied))
+	}
+
+	//weighing the factors
+	result := w.Rules*rulesScore + w.Unknowns*(1-float64(unknowns)/float64(len(toks))) - w.Fuzzy*fuzzy/float64(len(toks))
+	if known > 0 {
+		result += w.Resolved * float64(resolved) / float64(known)
+	}
+	if result < 0 {
+		return 0
+	}
+	if result > 1 {
+		return 1
+	}
+	return result
+}
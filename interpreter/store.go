@@ -0,0 +1,279 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package interpreter
+
+import (
+	"context"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	goahocorasick "github.com/anknown/ahocorasick"
+)
+
+/*
+ * This file contains the concurrent store of the dictionaries and their tokenizers
+ */
+
+//snapshot is an immutable version of the dictionary of an id along with its tokenizer.
+//A new snapshot is created whenever the dictionary changes, so the readers never see a partial update
+type snapshot struct {
+	//dict is the dictionary of the snapshot. It must not be mutated
+	dict DICT
+	//tokenizer has the automaton built for the dictionary
+	tokenizer Tokenizer
+	//version of the dictionary
+	version uint64
+	//words is the fingerprint of the words in the dictionary. The tokenizer is rebuilt only if the words change
+	words fingerprint
+	//lastUsed is the unix nano time at which the snapshot was used last. It is accessed atomically
+	lastUsed int64
+}
+
+//touch updates the last used time of the snapshot
+func (s *snapshot) touch() {
+	atomic.StoreInt64(&s.lastUsed, time.Now().UnixNano())
+}
+
+//DICT returns the dictionary of the snapshot with its last used time
+func (s *snapshot) DICT() DICT {
+	return DICT{LastUsed: time.Unix(0, atomic.LoadInt64(&s.lastUsed)), Map: s.dict.Map}
+}
+
+//load is an in flight load of a dictionary from the aggregator
+type load struct {
+	done chan struct{}
+	snap *snapshot
+	ok   bool
+}
+
+//loadKey is the key of an in flight load. The loads updating the dictionary are kept apart from the normal loads
+//so that an update is never dropped by joining a load which may return the stale dictionary
+type loadKey struct {
+	id     string
+	update bool
+}
+
+//store is the concurrent store of the dictionaries. Reads are lock free and the loads of an id
+//from the aggregator are single flighted so that concurrent requests for an id doesn't hit the aggregator more than once
+type store struct {
+	//snapshots has the snapshot of each id
+	snapshots sync.Map
+	//version is the last version assigned to a snapshot
+	version uint64
+	//loads has the in flight loads of each id
+	loads  map[loadKey]*load
+	loadsM sync.Mutex
+}
+
+//dictionaries is the store of the dictionaries in the platform
+var dictionaries = &store{loads: map[loadKey]*load{}}
+
+//fingerprint is an order independent hash of the words and the aliases of the nodes in a dictionary along with the normaliser applied to them
+type fingerprint struct {
+	count      int
+	sum        uint64
+	xor        uint64
+	normaliser string
+}
+
+//wordsFingerprint returns the fingerprint of the words in the dictionary normalised with the normaliser
+func wordsFingerprint(d DICT, n Normaliser) fingerprint {
+	f := fingerprint{normaliser: normaliserName(n)}
+	for word, tok := range d.Map {
+		if len(word) == 0 {
+			continue
+		}
+		f.add(word)
+		for _, node := range tok.Nodes {
+			for _, alias := range nodeAliases(node) {
+				f.add(alias)
+			}
+		}
+	}
+	return f
+}
+
+//add adds the word to the fingerprint
+func (f *fingerprint) add(word string) {
+	h := fnv.New64a()
+	h.Write([]byte(strings.ToLower(word)))
+	v := h.Sum64()
+	f.count++
+	f.sum += v
+	f.xor ^= v
+}
+
+//sameNodes returns true if each word in the dictionaries have the same nodes
+func sameNodes(a, b map[string]Token) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for word, tok := range b {
+		existing, ok := a[word]
+		if !ok || len(existing.Nodes) != len(tok.Nodes) {
+			return false
+		}
+		for i := range tok.Nodes {
+			if existing.Nodes[i] != tok.Nodes[i] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+//newSnapshot returns the snapshot of the dictionary. The words of the dictionary are normalised with the normaliser in the tokenizer config.
+//If the words of the dictionary are same as that of the previous snapshot, its automaton is reused. Else the automaton is built for the dictionary.
+//If the nodes of the words are also same, the whole tokenizer of the previous snapshot is reused
+func (s *store) newSnapshot(d DICT, previous *snapshot) (*snapshot, error) {
+	n := GetTokenizerConfig().Normaliser
+	snap := &snapshot{
+		dict:    DICT{Map: d.Map},
+		version: atomic.AddUint64(&s.version, 1),
+		words:   wordsFingerprint(d, n),
+	}
+	snap.touch()
+	if previous != nil && previous.words == snap.words && sameNodes(previous.dict.Map, d.Map) {
+		snap.tokenizer = previous.tokenizer
+		snap.tokenizer.Map = d.Map
+		return snap, nil
+	}
+	words := normalisedWords(d.Map, n)
+	if previous != nil && previous.words == snap.words {
+		snap.tokenizer = Tokenizer{Map: d.Map, Machine: previous.tokenizer.Machine, Normaliser: n, words: words, fuzzy: previous.tokenizer.fuzzy}
+		return snap, nil
+	}
+	m := new(goahocorasick.Machine)
+	patterns := [][]rune{}
+	for word := range words {
+		patterns = append(patterns, []rune(word))
+	}
+	if err := m.Build(patterns); err != nil {
+		return nil, err
+	}
+	snap.tokenizer = Tokenizer{Map: d.Map, Machine: m, Normaliser: n, words: words, fuzzy: &fuzzyIndex{}}
+	return snap, nil
+}
+
+//add adds the dictionary for the id replacing the existing one
+func (s *store) add(id string, d DICT) error {
+	var previous *snapshot
+	if v, ok := s.snapshots.Load(id); ok {
+		previous = v.(*snapshot)
+	}
+	snap, err := s.newSnapshot(d, previous)
+	if err != nil {
+		return err
+	}
+	s.snapshots.Store(id, snap)
+	return nil
+}
+
+//get returns the snapshot of the id if it is available in the store
+func (s *store) get(id string) (*snapshot, bool) {
+	v, ok := s.snapshots.Load(id)
+	if !ok {
+		return nil, false
+	}
+	snap := v.(*snapshot)
+	snap.touch()
+	return snap, true
+}
+
+//remove removes the dictionary of the id from the store
+func (s *store) remove(id string) {
+	s.snapshots.Delete(id)
+}
+
+//removeExpired removes the dictionaries not used for the expiry duration
+func (s *store) removeExpired(expiry time.Duration) {
+	t := time.Now().Add(-expiry).UnixNano()
+	s.snapshots.Range(func(k, v interface{}) bool {
+		if atomic.LoadInt64(&v.(*snapshot).lastUsed) < t {
+			s.snapshots.Delete(k)
+		}
+		return true
+	})
+}
+
+//fetch returns the snapshot of the id. If it is not available in the store or update is true, it is loaded from the aggregator.
+//Concurrent loads of the same id share a single call to the aggregator. An update never joins a normal load of the id.
+//If the context is done before the load completes, the error of the context is returned while the load continues in the background
+func (s *store) fetch(ctx context.Context, id string, update bool) (*snapshot, bool, error) {
+	/*
+	 * If the dictionary exists in the store and update is not required we will return it
+	 * Else we will join the in flight load of the id or start a new one
+	 * Then we will wait for the load to complete or the context to be done
+	 */
+	if !update {
+		if snap, ok := s.get(id); ok {
+			return snap, true, nil
+		}
+	}
+
+	//joining or starting the load
+	s.loadsM.Lock()
+	key := loadKey{id: id, update: update}
+	l, ok := s.loads[key]
+	if !ok {
+		l = &load{done: make(chan struct{})}
+		s.loads[key] = l
+		go s.load(key, l)
+	}
+	s.loadsM.Unlock()
+
+	//waiting for the load
+	select {
+	case <-l.done:
+		return l.snap, l.ok, nil
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	}
+}
+
+//load loads the dictionary of the key from the aggregator and stores it
+func (s *store) load(key loadKey, l *load) {
+	defer func() {
+		s.loadsM.Lock()
+		delete(s.loads, key)
+		s.loadsM.Unlock()
+		close(l.done)
+	}()
+	d, ok := getDICT(key.id, key.update)
+	if !ok {
+		if key.update {
+			s.remove(key.id)
+		}
+		return
+	}
+	if err := s.add(key.id, d); err != nil {
+		return
+	}
+	l.snap, l.ok = s.get(key.id)
+}
+
+//AddDICT adds the dictionary for the given id. The tokenizer for the dictionary is built once while adding.
+//If the words in the dictionary are same as the existing dictionary of the id, the existing tokenizer is reused
+func AddDICT(id string, d DICT) error {
+	return dictionaries.add(id, d)
+}
+
+//GetDICT returns the dictionary of the given id. If it is not available, it is loaded from the default aggregator.
+//The returned dictionary is shared with the tokenizer and must not be mutated. Use Copy to get a mutable copy
+func GetDICT(id string) (DICT, bool) {
+	snap, ok, _ := dictionaries.fetch(context.Background(), id, false)
+	if !ok {
+		return DICT{}, false
+	}
+	return snap.DICT(), true
+}
+
+//RemoveDICT removes the dictionary and the tokenizer of the given id
+func RemoveDICT(id string) {
+	dictionaries.remove(id)
+}
@@ -0,0 +1,233 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package interpreter_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cuttle-ai/octopus/interpreter"
+)
+
+/*
+ * This file contains the tests and benchmarks for the dictionary store
+ */
+
+//tenantAggregator is an aggregator returning a dictionary for the ids starting with tenant-
+type tenantAggregator struct {
+	calls   int32
+	updates int32
+	delay   time.Duration
+}
+
+func (t *tenantAggregator) Get(ID string, update bool) (interpreter.DICT, error) {
+	if !strings.HasPrefix(ID, "tenant-") {
+		return interpreter.DICT{}, errors.New("couldn't find the dictionary for " + ID)
+	}
+	atomic.AddInt32(&t.calls, 1)
+	if update {
+		atomic.AddInt32(&t.updates, 1)
+	}
+	if strings.HasPrefix(ID, "tenant-slow") {
+		time.Sleep(t.delay)
+	}
+	return tenantDICT(ID), nil
+}
+
+//tenantDICT returns a dictionary for the tenant
+func tenantDICT(id string) interpreter.DICT {
+	table := &interpreter.TableNode{UID: id, Name: id}
+	cars := &interpreter.ColumnNode{UID: id + "-cars", PUID: id, PN: table, Word: []rune("cars"), Name: "cars"}
+	brand := &interpreter.ColumnNode{UID: id + "-brand", PUID: id, PN: table, Word: []rune("brand"), Name: "brand"}
+	swift := &interpreter.ValueNode{UID: id + "-swift", PUID: cars.UID, PN: cars, Word: []rune("swift"), Name: "Swift"}
+	return interpreter.DICT{Map: map[string]interpreter.Token{
+		"cars":  {Word: []rune("cars"), Nodes: []interpreter.Node{cars}},
+		"brand": {Word: []rune("brand"), Nodes: []interpreter.Node{brand}},
+		"swift": {Word: []rune("swift"), Nodes: []interpreter.Node{swift}},
+	}}
+}
+
+func TestStore(t *testing.T) {
+	agg := &tenantAggregator{delay: 2 * time.Second}
+	interpreter.SetDefaultDICTAggregator(agg)
+	defer interpreter.SetDefaultDICTAggregator(nil)
+
+	//concurrent loads of an id are single flighted
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok := interpreter.GetDICT("tenant-single-flight"); !ok {
+				t.Error("Expected the dictionary to be loaded from the aggregator")
+			}
+		}()
+	}
+	wg.Wait()
+	if calls := atomic.LoadInt32(&agg.calls); calls != 1 {
+		t.Error("Expected the aggregator to be called once. Got", calls)
+	}
+
+	//a slow load doesn't block the other ids
+	go interpreter.GetDICT("tenant-slow")
+	time.Sleep(10 * time.Millisecond)
+	start := time.Now()
+	toks, err := interpreter.TokenizeContext(context.Background(), "tenant-fast", []rune("swift cars"))
+	if err != nil || len(toks) == 0 {
+		t.Error("Expected the sentence to be tokenized. Got", toks, err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Error("Expected the tokenizer not to wait for the slow load of another id. Took", d)
+	}
+
+	//waiting for a slow load can be given up
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := interpreter.TokenizeContext(ctx, "tenant-slow-timeout", []rune("swift cars")); err != interpreter.ErrTimeout {
+		t.Error("Expected the timeout error while waiting for the slow load. Got", err)
+	}
+
+	//re-adding a dictionary with the same words reflects the new nodes
+	d := tenantDICT("readded")
+	interpreter.AddDICT("readded", d)
+	renamed := tenantDICT("readded-renamed")
+	interpreter.AddDICT("readded", renamed)
+	toks, err = interpreter.TokenizeContext(context.Background(), "readded", []rune("swift cars"))
+	if err != nil || len(toks) == 0 || len(toks[0].Values) == 0 || toks[0].Values[0].UID != "readded-renamed-swift" {
+		t.Error("Expected the nodes of the re-added dictionary. Got", toks, err)
+	}
+
+	//the result of adding through the channel is sent back to the requester
+	out := make(chan interpreter.DICTRequest, 1)
+	interpreter.SendDICTToChannel(interpreter.DICTInputChannel, interpreter.DICTRequest{ID: "channel", Type: interpreter.DICTAdd, DICT: tenantDICT("channel"), Out: out})
+	if res := <-out; !res.Valid {
+		t.Error("Expected the dictionary added through the channel to be valid")
+	}
+	interpreter.SendDICTToChannel(interpreter.DICTInputChannel, interpreter.DICTRequest{ID: "channel-empty", Type: interpreter.DICTAdd, DICT: interpreter.DICT{}, Out: out})
+	if res := <-out; res.Valid {
+		t.Error("Expected the empty dictionary not to be added")
+	}
+
+	//removed dictionaries are not available without the aggregator
+	interpreter.AddDICT("removed", tenantDICT("removed"))
+	interpreter.RemoveDICT("removed")
+	if _, ok := interpreter.GetDICT("removed"); ok {
+		t.Error("Expected the removed dictionary to be not found")
+	}
+	//an update during a normal load of the id is not dropped
+	agg = &tenantAggregator{delay: 100 * time.Millisecond}
+	interpreter.SetDefaultDICTAggregator(agg)
+	go interpreter.GetDICT("tenant-slow-update")
+	time.Sleep(10 * time.Millisecond)
+	interpreter.SendDICTToChannel(interpreter.DICTInputChannel, interpreter.DICTRequest{ID: "tenant-slow-update", Type: interpreter.DICTUpdate})
+	for i := 0; i < 100 && atomic.LoadInt32(&agg.updates) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if updates := atomic.LoadInt32(&agg.updates); updates != 1 {
+		t.Error("Expected the aggregator to be called once for the update. Got", updates)
+	}
+}
+
+//benchmarkTenants is the no. of tenants in the benchmarks
+const benchmarkTenants = 1000
+
+func loadTenants() {
+	for i := 0; i < benchmarkTenants; i++ {
+		id := "bench-" + strconv.Itoa(i)
+		interpreter.AddDICT(id, tenantDICT(id))
+	}
+}
+
+func BenchmarkGetDICTTenants(b *testing.B) {
+	loadTenants()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			interpreter.GetDICT("bench-" + strconv.Itoa(i%benchmarkTenants))
+			i++
+		}
+	})
+}
+
+func BenchmarkTokenizeTenants(b *testing.B) {
+	loadTenants()
+	sentence := []rune("show me the swift cars by brand")
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if _, err := interpreter.TokenizeContext(context.Background(), "bench-"+strconv.Itoa(i%benchmarkTenants), sentence); err != nil {
+				b.Error(err)
+			}
+			i++
+		}
+	})
+}
+
+//largeDICTWords is the no. of words in the large dictionary benchmarks
+const largeDICTWords = 100000
+
+//largeDICT returns a dictionary of the tenant with the given no. of words in addition to its columns
+func largeDICT(id string, words int) interpreter.DICT {
+	d := tenantDICT(id)
+	table := &interpreter.TableNode{UID: id, Name: id}
+	for i := 0; i < words; i++ {
+		word := "value" + strconv.Itoa(i)
+		v := &interpreter.ValueNode{UID: id + "-" + word, PUID: id, PN: &interpreter.ColumnNode{UID: id + "-cars", PN: table}, Word: []rune(word), Name: word}
+		d.Map[word] = interpreter.Token{Word: []rune(word), Nodes: []interpreter.Node{v}}
+	}
+	return d
+}
+
+//BenchmarkTokenizeLargeRebuild adds a dictionary with changed words before every query.
+//So the automaton is rebuilt for every query as it used to be before the dictionaries were fingerprinted
+func BenchmarkTokenizeLargeRebuild(b *testing.B) {
+	dicts := []interpreter.DICT{largeDICT("large-rebuild", largeDICTWords), largeDICT("large-rebuild", largeDICTWords+1)}
+	sentence := []rune("show me the swift cars by brand for value4242")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := interpreter.AddDICT("large-rebuild", dicts[i%2]); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := interpreter.TokenizeContext(context.Background(), "large-rebuild", sentence); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+//BenchmarkTokenizeLargeUnchanged adds the same dictionary before every query. The automaton is reused
+//as the words of the dictionary doesn't change
+func BenchmarkTokenizeLargeUnchanged(b *testing.B) {
+	d := largeDICT("large-unchanged", largeDICTWords)
+	sentence := []rune("show me the swift cars by brand for value4242")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := interpreter.AddDICT("large-unchanged", d); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := interpreter.TokenizeContext(context.Background(), "large-unchanged", sentence); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+//BenchmarkTokenizeLarge tokenizes with the dictionary already in the store
+func BenchmarkTokenizeLarge(b *testing.B) {
+	interpreter.AddDICT("large", largeDICT("large", largeDICTWords))
+	sentence := []rune("show me the swift cars by brand for value4242")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := interpreter.TokenizeContext(context.Background(), "large", sentence); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -4,12 +4,26 @@
 
 package interpreter
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 /*
  * This file contains the defnition of table type node
  */
 
+//ForeignKey is the relationship of a column in a table with a column in another table.
+//It is used for finding the join path between the tables referred in a query
+type ForeignKey struct {
+	//Column is the name of the column in the table having the foreign key
+	Column string `json:"column,omitempty"`
+	//RefTableUID is the uid of the table being referred
+	RefTableUID string `json:"ref_table_uid,omitempty"`
+	//RefColumn is the name of the column being referred in the referred table
+	RefColumn string `json:"ref_column,omitempty"`
+}
+
 //TableNode is the node storing the information about a table.
 //It can be the child of a KnowledgeBase and can have Column as children
 type TableNode struct {
@@ -35,6 +49,15 @@ type TableNode struct {
 	Description string
 	//DatastoreID is Datastore to which the table belongs to
 	DatastoreID uint
+	//ForeignKeys has the relationships of the table with other tables
+	ForeignKeys []ForeignKey
+	//FiscalYearStart is the month in which the fiscal year of the table starts.
+	//If not set, the fiscal year is same as the calendar year
+	FiscalYearStart time.Month
+	//Aliases are the other words with which the table node can be matched
+	Aliases []string
+	//MatchedAlias is the alias with which the node was matched in the sentence. It is empty if the node was matched with its word
+	MatchedAlias string
 }
 
 type tableNode struct {
@@ -49,6 +72,10 @@ type tableNode struct {
 	DefaultDateField    *ColumnNode  `json:"default_date_field,omitempty"`
 	Description         string       `json:"description"`
 	DatastoreID         uint         `json:"datastore_id"`
+	ForeignKeys         []ForeignKey `json:"foreign_keys,omitempty"`
+	FiscalYearStart     time.Month   `json:"fiscal_year_start,omitempty"`
+	Aliases             []string     `json:"aliases,omitempty"`
+	MatchedAlias        string       `json:"matched_alias,omitempty"`
 }
 
 //Copy will return a copy of the node
@@ -65,6 +92,10 @@ func (t *TableNode) Copy() Node {
 		DefaultDateField:    t.DefaultDateField,
 		Description:         t.Description,
 		DatastoreID:         t.DatastoreID,
+		ForeignKeys:         t.ForeignKeys,
+		FiscalYearStart:     t.FiscalYearStart,
+		Aliases:             t.Aliases,
+		MatchedAlias:        t.MatchedAlias,
 	}
 }
 
@@ -96,7 +127,8 @@ func (t *TableNode) Parent() Node {
 //MarshalJSON encodes the node into a serializable json
 func (t *TableNode) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&tableNode{
-		t.UID, string(t.Word), t.PUID, t.Name, t.Children, t.Resolved, "Table", t.DefaultDateFieldUID, t.DefaultDateField, t.Description, t.DatastoreID,
+		t.UID, string(t.Word), t.PUID, t.Name, t.Children, t.Resolved, "Table", t.DefaultDateFieldUID, t.DefaultDateField, t.Description, t.DatastoreID, t.ForeignKeys,
+		t.FiscalYearStart, t.Aliases, t.MatchedAlias,
 	})
 }
 
@@ -117,6 +149,10 @@ func (t *TableNode) UnmarshalJSON(data []byte) error {
 	t.DefaultDateField = m.DefaultDateField
 	t.Description = m.Description
 	t.DatastoreID = m.DatastoreID
+	t.ForeignKeys = m.ForeignKeys
+	t.FiscalYearStart = m.FiscalYearStart
+	t.Aliases = m.Aliases
+	t.MatchedAlias = m.MatchedAlias
 	return nil
 }
 
@@ -129,3 +165,15 @@ func (t *TableNode) IsResolved() bool {
 func (t *TableNode) SetResolved(state bool) {
 	t.Resolved = state
 }
+
+//FiscalYear returns the month in which the fiscal year of the table starts.
+//If the table doesn't have one, the fiscal year of its knowledge base is used. Else the fiscal year is same as the calendar year
+func (t *TableNode) FiscalYear() time.Month {
+	if t.FiscalYearStart != 0 {
+		return t.FiscalYearStart
+	}
+	if kb, ok := t.PN.(*KnowledgeBaseNode); ok && kb != nil && kb.FiscalYearStart != 0 {
+		return kb.FiscalYearStart
+	}
+	return time.January
+}
@@ -20,6 +20,21 @@ type Token struct {
 	Word []rune
 	//Node has the list of nodes applicable to a token
 	Nodes []Node
+	//MatchedAliases has the alias with which each of the nodes was matched. It is empty for the nodes matched with their word
+	MatchedAliases []string
+	//Discarded has the dictionary matches overlapping the token which were discarded in favour of the token.
+	//They are kept for debugging and their positions refer to the original sentence
+	Discarded []Token
+	//Fuzzy has the details of the dictionary word matched if the token is a fuzzy match of a misspelled word
+	Fuzzy *FuzzyMatch
+}
+
+//matchedAlias returns the alias with which the node at the index was matched
+func (t Token) matchedAlias(i int) string {
+	if i < len(t.MatchedAliases) {
+		return t.MatchedAliases[i]
+	}
+	return ""
 }
 
 //FastToken is used to store the token with nodes converted into their concrete type so
@@ -41,13 +56,24 @@ type FastToken struct {
 	Unknowns []UnknownNode
 	//Times is the list of time nodes in the token
 	Times []TimeNode
+	//Ranks is the list of rank nodes in the token
+	Ranks []RankNode
+	//Numbers is the list of number nodes in the token
+	Numbers []NumberNode
+	//Discarded has the dictionary matches overlapping the token which were discarded in favour of the token
+	Discarded []FastToken
+	//Fuzzy has the details of the dictionary word matched if the token is a fuzzy match of a misspelled word
+	Fuzzy *FuzzyMatch
 }
 
 //FastToken returns the converted fast token of the token
 func (t Token) FastToken() FastToken {
-	result := FastToken{Pos: t.Pos, Word: t.Word}
+	result := FastToken{Pos: t.Pos, Word: t.Word, Fuzzy: t.Fuzzy}
+	for _, d := range t.Discarded {
+		result.Discarded = append(result.Discarded, d.FastToken())
+	}
 
-	for _, n := range t.Nodes {
+	for i, n := range t.Nodes {
 		switch n.Type() {
 		case Table:
 			tab, ok := n.(*TableNode)
@@ -56,6 +82,9 @@ func (t Token) FastToken() FastToken {
 					result.Tables = []TableNode{}
 				}
 				result.Tables = append(result.Tables, *tab)
+				if alias := t.matchedAlias(i); len(alias) != 0 {
+					result.Tables[len(result.Tables)-1].MatchedAlias = alias
+				}
 			}
 		case Column:
 			col, ok := n.(*ColumnNode)
@@ -64,6 +93,9 @@ func (t Token) FastToken() FastToken {
 					result.Columns = []ColumnNode{}
 				}
 				result.Columns = append(result.Columns, *col)
+				if alias := t.matchedAlias(i); len(alias) != 0 {
+					result.Columns[len(result.Columns)-1].MatchedAlias = alias
+				}
 			}
 		case Value:
 			val, ok := n.(*ValueNode)
@@ -72,6 +104,9 @@ func (t Token) FastToken() FastToken {
 					result.Values = []ValueNode{}
 				}
 				result.Values = append(result.Values, *val)
+				if alias := t.matchedAlias(i); len(alias) != 0 {
+					result.Values[len(result.Values)-1].MatchedAlias = alias
+				}
 			}
 		case Operator:
 			op, ok := n.(*OperatorNode)
@@ -97,6 +132,22 @@ func (t Token) FastToken() FastToken {
 				}
 				result.Times = append(result.Times, *tn)
 			}
+		case Rank:
+			rn, ok := n.(*RankNode)
+			if ok {
+				if result.Ranks == nil {
+					result.Ranks = []RankNode{}
+				}
+				result.Ranks = append(result.Ranks, *rn)
+			}
+		case Number:
+			nn, ok := n.(*NumberNode)
+			if ok {
+				if result.Numbers == nil {
+					result.Numbers = []NumberNode{}
+				}
+				result.Numbers = append(result.Numbers, *nn)
+			}
 		}
 	}
 
@@ -105,7 +156,7 @@ func (t Token) FastToken() FastToken {
 
 //Copy makes a deep copy of the token
 func (t Token) Copy() Token {
-	res := Token{Pos: t.Pos, Word: t.Word, Nodes: []Node{}}
+	res := Token{Pos: t.Pos, Word: t.Word, Nodes: []Node{}, MatchedAliases: t.MatchedAliases}
 	for _, v := range t.Nodes {
 		res.Nodes = append(res.Nodes, v.Copy())
 	}
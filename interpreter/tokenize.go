@@ -5,12 +5,14 @@
 package interpreter
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sort"
 	"strconv"
-	"strings"
+	"sync"
 	"time"
+	"unicode"
 
 	goahocorasick "github.com/anknown/ahocorasick"
 	"github.com/cuttle-ai/octopus/datetime"
@@ -20,59 +22,121 @@ import (
  * This file contains the utilities for tokenizing a sentence.
  */
 
-//Tokenize will tokenize a given sentence according to the tokenizer of the given id
+//ErrDictionaryNotFound is returned when the dictionary for the id couldn't be found while tokenizing
+var ErrDictionaryNotFound = errors.New("couldn't find the dictionary for tokenizing the sentence")
+
+//ErrTimeout is returned when the deadline of the context exceeded while tokenizing or interpreting
+var ErrTimeout = errors.New("timed out while interpreting the sentence")
+
+//DateServiceTimeout is the maximum time to wait for the datetime service while tokenizing.
+//If the service doesn't respond within the time, the sentence is tokenized without the date/time
+var DateServiceTimeout = 3 * time.Second
+
+//Tokenize will tokenize a given sentence according to the tokenizer of the given id.
+//The date/time in the sentence is resolved against the current time in the server's timezone
 func Tokenize(id string, sentence []rune) ([]FastToken, error) {
+	return TokenizeContextWithOptions(context.Background(), id, sentence, datetime.Options{})
+}
+
+//TokenizeWithOptions will tokenize a given sentence according to the tokenizer of the given id.
+//The date/time in the sentence is resolved with the reference time, locale and timezone in the given options
+func TokenizeWithOptions(id string, sentence []rune, opts datetime.Options) ([]FastToken, error) {
+	return TokenizeContextWithOptions(context.Background(), id, sentence, opts)
+}
+
+//TokenizeContext will tokenize a given sentence according to the tokenizer of the given id.
+//If the context is done before the sentence is tokenized, ErrTimeout or the error of the context is returned
+func TokenizeContext(ctx context.Context, id string, sentence []rune) ([]FastToken, error) {
+	return TokenizeContextWithOptions(ctx, id, sentence, datetime.Options{})
+}
+
+//TokenizeContextWithOptions will tokenize a given sentence according to the tokenizer of the given id with the datetime options.
+//If the context is done before the sentence is tokenized, ErrTimeout or the error of the context is returned.
+//If the dictionary for the id couldn't be found, ErrDictionaryNotFound is returned
+func TokenizeContextWithOptions(ctx context.Context, id string, sentence []rune, opts datetime.Options) ([]FastToken, error) {
 	/*
+	 * We will mask the quoted literals in the sentence so that they are not matched
 	 * We will initiate the datetime service
-	 * Then we will prepcache the dictionary for the id
-	 * We will make a request to the tokenizer to get the sentence tokenized
-	 * Then we will build the unknowns
-	 * Then we will adjust the date nodes
+	 * Then we will get the tokenizer for the id from the store and tokenize the sentence
+	 * Then we will wait for the datetime service and build the date nodes
+	 * Then we will build the number nodes
+	 * Then we will build the literals and unknowns and match them with the dictionary if fuzzy matching is enabled
 	 * Then we will adjust the postions
 	 * Then we will do a fast token for all the tokens and return the same
+	 * Waiting for the dictionary and the dates are given up if the context is done
 	 */
+	if ctx.Err() != nil {
+		return nil, contextError(ctx)
+	}
+
+	//quoted literals bypass the dictionary, dates and numbers
+	masked := maskLiterals(sentence)
+
 	//start checking for the dates
-	ch, err := StartCheckingForDates(sentence)
+	//the date service is cancelled once we stop waiting for it
+	dateCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ch, err := StartCheckingForDates(dateCtx, masked, opts)
 	if err != nil {
 		//error while checking for the dates
 		return nil, err
 	}
 
-	//precaching
-	dictReq := DICTRequest{ID: id, Type: DICTPreCache, Out: make(chan DICTRequest)}
-	SendDICTToChannel(DICTInputChannel, dictReq)
-	<-dictReq.Out
-
-	//requesting the tokenizer to tokenize the given sentence
-	req := Request{
-		ID:       id,
-		Type:     TokenizerGet,
-		Sentence: sentence,
-		Out:      make(chan Request),
+	//getting the tokenizer of the id from the store
+	//the dictionary is loaded from the aggregator if it is not available in the store
+	snap, ok, err := dictionaries.fetch(ctx, id, false)
+	if err != nil {
+		return nil, contextError(ctx)
 	}
-	go SendTokenizerToChannel(TokenizerInputChannel, req)
-	res := <-req.Out
-	if !res.Valid {
-		return nil, errors.New("couldn't tokenize the given sentence for the id " + id)
+	if !ok {
+		return nil, ErrDictionaryNotFound
+	}
+	matches := snap.tokenizer.match(masked)
+
+	//waiting for the date service
+	result := datetime.Results{}
+	select {
+	case result = <-ch:
+	case <-time.After(DateServiceTimeout):
+	case <-ctx.Done():
+		return nil, contextError(ctx)
 	}
 
 	//adjusting the date fields
 	//adding the date fields before adding unknowns is important as the
 	//positions still refers to the original position in the sentence
-	res.Matches = BuildTimeNodes(res.Matches, ch)
+	matches = buildTimeNodes(matches, result)
 
-	//building the unknowns
-	res.Matches = BuildUnknowns(sentence, res.Matches)
+	//adding the numbers
+	//numbers are added after the dates so that the years, days etc. in the dates are not considered as numbers
+	matches = BuildNumberNodes(masked, matches)
+
+	//building the unknowns along with the quoted literals
+	matches = BuildUnknowns(sentence, matches)
+
+	//matching the misspelled words
+	if config := GetTokenizerConfig(); config.Fuzzy.Enabled {
+		matches = snap.tokenizer.FuzzyMatch(matches, config.Fuzzy)
+	}
 
 	//adjusting the positions of the tokens according to the position in the token list
-	res.Matches = AdjustPositions(res.Matches)
+	matches = AdjustPositions(matches)
 
 	//fast tokenizing the tokens
-	result := []FastToken{}
-	for _, v := range res.Matches {
-		result = append(result, v.FastToken())
+	fastToks := []FastToken{}
+	for _, v := range matches {
+		fastToks = append(fastToks, v.FastToken())
 	}
-	return result, nil
+	return fastToks, nil
+}
+
+//contextError returns the error to be returned when the context is done.
+//ErrTimeout is returned if the deadline of the context exceeded
+func contextError(ctx context.Context) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return ErrTimeout
+	}
+	return ctx.Err()
 }
 
 //RequestType is the type of the request for the tokenizer
@@ -93,6 +157,12 @@ type Tokenizer struct {
 	Machine *goahocorasick.Machine
 	//map has the tokens mapped to their word
 	Map map[string]Token
+	//Normaliser is the normaliser applied to the words in the dictionary and the sentence. If nil, they are only lower cased
+	Normaliser Normaliser
+	//words has the tokens mapped to their normalised word. Tokens of the words having the same normal form are merged
+	words map[string]Token
+	//fuzzy has the index of the words for fuzzy matching
+	fuzzy *fuzzyIndex
 }
 
 //Request can be used to make a request to tokenizer cache
@@ -109,7 +179,8 @@ type Request struct {
 	Valid bool
 	//matches returns the matched tokens
 	Matches []Token
-	//Out channel for sending response to the requester
+	//Out channel for sending response to the requester.
+	//It is optional for add requests. If given, the request is sent back with Valid false if the tokenizer couldn't be added
 	Out chan Request
 }
 
@@ -127,67 +198,214 @@ func SendTokenizerToChannel(ch chan Request, req Request) {
 	ch <- req
 }
 
-//Cache is the cache for providing the tokenizer to the platform on demand
+//Cache serves the requests to the tokenizers coming through the channel.
+//The tokenizers are kept along with the dictionaries in the concurrent store,
+//so adding a tokenizer adds the dictionary for the id and removing it removes the dictionary as well.
+//The add requests are served in order, so building a large tokenizer delays the requests behind it
 func Cache(in chan Request) {
 	/*
 	 * We will go into an infinte loop
 	 * Will wait for the requests to come through the channel
-	 * Based on the type of the request we will remove them from memory
+	 * Based on the type of the request we will update the store or tokenize the sentence concurrently
 	 */
-	dict := make(map[string]Tokenizer)
 	for {
 		req := <-in
 		switch req.Type {
 		case TokenizerAdd:
-			m := new(goahocorasick.Machine)
-			d := [][]rune{}
-			for word := range req.Tokenizer.Map {
-				if len(word) == 0 {
-					continue
-				}
-				d = append(d, []rune(strings.ToLower(word)))
-			}
-			if err := m.Build(d); err != nil {
-				fmt.Println(err)
-				break
+			err := dictionaries.add(req.ID, DICT{Map: req.Tokenizer.Map})
+			if req.Out != nil {
+				req.Valid = err == nil
+				go SendTokenizerToChannel(req.Out, req)
 			}
-			dict[req.ID] = Tokenizer{Map: req.Tokenizer.Map, Machine: m}
 			break
 		case TokenizerGet:
-			t, mOk := dict[req.ID]
-			if !mOk {
+			snap, ok := dictionaries.get(req.ID)
+			if !ok {
 				req.Valid = false
 				go SendTokenizerToChannel(req.Out, req)
 				break
 			}
-			terms := t.Machine.MultiPatternSearch([]rune(strings.ToLower(string(req.Sentence))), false)
-			result := []Token{}
-			for _, term := range terms {
-				tok, ok := t.Map[string(term.Word)]
-				if ok {
-					result = append(result, Token{Pos: term.Pos, Word: term.Word, Nodes: tok.Nodes})
-				}
-			}
-			req.Matches = result
-			req.Valid = true
-			go SendTokenizerToChannel(req.Out, req)
+			go func(req Request) {
+				req.Matches = snap.tokenizer.match(req.Sentence)
+				req.Valid = true
+				SendTokenizerToChannel(req.Out, req)
+			}(req)
 			break
 		case TokenizerRemove:
-			delete(dict, req.ID)
+			dictionaries.remove(req.ID)
 		}
 	}
 }
 
+//TokenizerConfig is the configuration of the tokenizer
+type TokenizerConfig struct {
+	//WordBoundary if true, the words in the dictionary are matched only at the word boundaries of the sentence.
+	//So is won't be matched inside this. Words starting or ending with symbols like < and >= can match next to any rune on that side
+	WordBoundary bool
+	//NodePriority is the priority of the node types used to resolve the overlapping matches of same length.
+	//Types appearing first have higher priority. Types not in the list have the least priority
+	NodePriority []Type
+	//Normaliser is applied to the words of the dictionary when the tokenizer is built and to the sentence while tokenizing.
+	//Changing the normaliser applies only to the dictionaries added after the change
+	Normaliser Normaliser
+	//Fuzzy is the configuration of the fuzzy matching of the unknown words
+	Fuzzy FuzzyConfig
+}
+
+//DefaultNodePriority is the default priority of the node types while resolving the overlapping matches
+var DefaultNodePriority = []Type{Table, Column, Value, Operator, Rank, Time, KnowledgeBase}
+
+//DefaultTokenizerConfig is the default configuration of the tokenizer
+var DefaultTokenizerConfig = TokenizerConfig{WordBoundary: true, NodePriority: DefaultNodePriority, Normaliser: DefaultNormaliser, Fuzzy: DefaultFuzzyConfig}
+
+var (
+	tokenizerConfig     = DefaultTokenizerConfig
+	tokenizerConfigLock sync.RWMutex
+)
+
+//SetTokenizerConfig sets the configuration to be used by the tokenizer
+func SetTokenizerConfig(c TokenizerConfig) {
+	tokenizerConfigLock.Lock()
+	tokenizerConfig = c
+	tokenizerConfigLock.Unlock()
+}
+
+//GetTokenizerConfig returns the configuration used by the tokenizer
+func GetTokenizerConfig() TokenizerConfig {
+	tokenizerConfigLock.RLock()
+	defer tokenizerConfigLock.RUnlock()
+	return tokenizerConfig
+}
+
+//isWordRune returns true if the rune can be part of a word. Letters, digits and the combining marks
+//of all the scripts are considered part of a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == '_'
+}
+
+//atWordBoundary returns true if the match of the given length at the position of the sentence starts and ends at the word boundaries.
+//A match starting with a symbol has no boundary on its start and a match ending with a symbol has no boundary on its end
+func atWordBoundary(sentence []rune, pos, length int) bool {
+	if length == 0 {
+		return false
+	}
+	end := pos + length
+	if pos > 0 && isWordRune(sentence[pos]) && isWordRune(sentence[pos-1]) {
+		return false
+	}
+	if end < len(sentence) && isWordRune(sentence[end-1]) && isWordRune(sentence[end]) {
+		return false
+	}
+	return true
+}
+
+//match returns the non overlapping tokens of the dictionary found in the sentence.
+//The sentence is normalised before matching while the words and the positions of the tokens refer to the original sentence
+func (t Tokenizer) match(sentence []rune) []Token {
+	norm := normaliseText(sentence, t.Normaliser)
+	terms := t.Machine.MultiPatternSearch(norm.text, false)
+	config := GetTokenizerConfig()
+	words := t.words
+	if words == nil {
+		words = t.Map
+	}
+	result := []Token{}
+	for _, term := range terms {
+		if config.WordBoundary && !atWordBoundary(norm.text, term.Pos, len(term.Word)) {
+			//the word is a part of another word in the sentence
+			continue
+		}
+		tok, ok := words[string(term.Word)]
+		if ok {
+			start, end := norm.span(term.Pos, len(term.Word))
+			result = append(result, Token{Pos: start, Word: sentence[start:end], Nodes: tok.Nodes, MatchedAliases: tok.MatchedAliases})
+		}
+	}
+	return ResolveOverlaps(result, config.NodePriority)
+}
+
+//ResolveOverlaps returns the non overlapping tokens sorted by their position.
+//Longest match is preferred among the overlapping tokens. If they are of the same length, the token having the node type with higher priority
+//is preferred and then the one appearing first in the sentence. The discarded tokens are added to the token overlapping them
+func ResolveOverlaps(toks []Token, priority []Type) []Token {
+	/*
+	 * We will rank the tokens by their length, the priority of their nodes and the position
+	 * Then we will pick the tokens in the order of the rank if they don't overlap with the tokens already picked
+	 * The tokens overlapping the picked tokens are added as the discarded tokens of the first of them
+	 * Then the picked tokens are sorted by their position
+	 */
+	//ranking the tokens
+	ranked := make([]Token, len(toks))
+	copy(ranked, toks)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if len(ranked[i].Word) != len(ranked[j].Word) {
+			return len(ranked[i].Word) > len(ranked[j].Word)
+		}
+		if pi, pj := nodePriority(ranked[i], priority), nodePriority(ranked[j], priority); pi != pj {
+			return pi < pj
+		}
+		if ranked[i].Pos != ranked[j].Pos {
+			return ranked[i].Pos < ranked[j].Pos
+		}
+		return string(ranked[i].Word) < string(ranked[j].Word)
+	})
+
+	//picking the tokens
+	picked := []Token{}
+	discarded := []Token{}
+	for _, tok := range ranked {
+		if overlapping(picked, tok) >= 0 {
+			discarded = append(discarded, tok)
+			continue
+		}
+		picked = append(picked, tok)
+	}
+	sort.Slice(picked, func(i, j int) bool { return picked[i].Pos < picked[j].Pos })
+
+	//adding the discarded tokens
+	for _, tok := range discarded {
+		i := overlapping(picked, tok)
+		picked[i].Discarded = append(picked[i].Discarded, tok)
+	}
+	return picked
+}
+
+//nodePriority returns the highest priority of the node types in the token. Lower the value, higher the priority
+func nodePriority(tok Token, priority []Type) int {
+	result := len(priority)
+	for _, n := range tok.Nodes {
+		for i, t := range priority {
+			if n.Type() == t && i < result {
+				result = i
+			}
+		}
+	}
+	return result
+}
+
+//overlapping returns the index of the first token overlapping the given token. If none of them overlaps, -1 is returned
+func overlapping(toks []Token, tok Token) int {
+	for i, t := range toks {
+		if tok.Pos < t.Pos+len(t.Word) && t.Pos < tok.Pos+len(tok.Word) {
+			return i
+		}
+	}
+	return -1
+}
+
 //BuildUnknowns build unknown nodes.
-//It return the tokens with unidentified words in the sentence transfomed to tokens with unknowns
+//It return the tokens with unidentified words in the sentence transfomed to tokens with unknowns.
+//Quoted spans in the sentence become single tokens with literal unknowns replacing the tokens inside them
 func BuildUnknowns(sentence []rune, toks []Token) []Token {
 	/*
+	 * We will add the quoted literals
 	 * We will iterate through the sentence
 	 * Till the positions of each token is reached we will itearte through the sentence and build each word
 	 * Upon reaching a position, it will add the word to the words list
 	 * Once all the unknown words are recorded, we will split each word based on space
 	 * The resultant words are consolidated as tokens
 	 */
+	toks = BuildLiterals(sentence, toks)
 	result := []Token{}
 	words := [][]rune{}
 	tok := 0
@@ -209,7 +427,11 @@ func BuildUnknowns(sentence []rune, toks []Token) []Token {
 			}
 			tokenMap[string(toks[tok].Word)] = toks[tok]
 			words = append(words, toks[tok].Word)
-			p = toks[tok].Pos + len(toks[tok].Word)
+			//the space following the token is skipped. Tokens like symbols can be followed by another token without a space
+			p = toks[tok].Pos + len(toks[tok].Word) - 1
+			if p+1 < len(sentence) && unicode.IsSpace(sentence[p+1]) {
+				p++
+			}
 			tok++
 		}
 	}
@@ -233,6 +455,8 @@ func BuildUnknowns(sentence []rune, toks []Token) []Token {
 		oTok, ok := tokenMap[string(w)]
 		if ok {
 			tok.Nodes = oTok.Nodes
+			tok.MatchedAliases = oTok.MatchedAliases
+			tok.Discarded = oTok.Discarded
 		} else {
 			tok.Nodes = []Node{&UnknownNode{UID: fmt.Sprint("U", i), Word: []rune(w)}}
 		}
@@ -252,19 +476,44 @@ func AdjustPositions(toks []Token) []Token {
 	result := []Token{}
 
 	for k, tok := range toks {
-		result = append(result, Token{Pos: k, Word: tok.Word, Nodes: tok.Nodes})
+		tok.Pos = k
+		result = append(result, tok)
 	}
 
 	return result
 }
 
-//StartCheckingForDates will initaite the service which starts checking for the date/time presence in the query
-func StartCheckingForDates(sentence []rune) (chan datetime.Results, error) {
+//StartCheckingForDates will initaite the service which starts checking for the date/time presence in the query.
+//The fiscal periods in the query are given priority over the results of the service
+func StartCheckingForDates(ctx context.Context, sentence []rune, opts datetime.Options) (chan datetime.Results, error) {
+	/*
+	 * We will get the date service
+	 * Then we will find the fiscal periods in the query
+	 * If there are fiscal periods, we will merge them with the results of the service
+	 */
 	ser, err := datetime.DefaultService()
 	if err != nil {
 		return nil, errors.New("Error while getting the date service" + err.Error())
 	}
-	return ser.Query(sentence), nil
+	now, err := opts.Now()
+	if err != nil {
+		return nil, errors.New("Error while getting the reference time for the dates" + err.Error())
+	}
+	fiscal := datetime.ParseFiscal(sentence, now)
+	if len(fiscal.Res) == 0 {
+		return ser.Query(ctx, sentence, opts), nil
+	}
+	//merging is given up once the context is done
+	ch := make(chan datetime.Results, 1)
+	go func(res chan datetime.Results) {
+		select {
+		case r := <-res:
+			ch <- fiscal.Merge(r)
+		case <-ctx.Done():
+			ch <- datetime.Results{Err: ctx.Err()}
+		}
+	}(ser.Query(ctx, sentence, opts))
+	return ch, nil
 }
 
 type timeResults []datetime.Response
@@ -277,20 +526,21 @@ func (r timeResults) Less(i, j int) bool { return r[i].Start < r[j].Start }
 //This function won't replace any existing tokens. If conflict between existing node and time node come,
 // the time node will be skipped with priority given to the existing node.
 func BuildTimeNodes(toks []Token, ch chan datetime.Results) []Token {
-	/*
-	 * Then we will wait for the channel to dump results
-	 * We will check whether the results are valid or not
-	 * Then we will process it
-	 */
 	//wait for the channel to dump response
-	result := &datetime.Results{}
+	result := datetime.Results{}
 	select {
-	case res := <-ch:
-		result = &res
-	case <-time.After(3 * time.Second):
-		break
+	case result = <-ch:
+	case <-time.After(DateServiceTimeout):
 	}
+	return buildTimeNodes(toks, result)
+}
 
+//buildTimeNodes will insert time nodes for the valid responses in the result from the date service
+func buildTimeNodes(toks []Token, result datetime.Results) []Token {
+	/*
+	 * We will check whether the results are valid or not
+	 * Then we will process it
+	 */
 	//checking whether the results are valid or not
 	if !result.IsValid() {
 		return toks
@@ -307,7 +557,7 @@ func BuildTimeNodes(toks []Token, ch chan datetime.Results) []Token {
 	}
 	sort.Sort(timeResults(validResults))
 	i, j := 0, 0
-	for i < len(validResults) {
+	for i < len(validResults) && j < len(toks) {
 		result := validResults[i]
 		startIndex := result.Start
 		endIndex := result.End
@@ -336,31 +586,27 @@ func BuildTimeNodes(toks []Token, ch chan datetime.Results) []Token {
 			//we haven't reached the relevant position in the token
 			j++
 		}
+	}
 
-		if j >= len(toks) {
-			//we have exhausted the token list now we will append
-			//the remianing time results to the tokens list
-			timeNodes := []Token{}
-			for i < len(validResults) {
-
-				result = validResults[i]
-				startIndex = result.Start
-				timeNodes = append(timeNodes, Token{
-					Pos:  startIndex,
-					Word: []rune(result.Body),
-					Nodes: []Node{
-						&TimeNode{
-							UID:   "T" + strconv.Itoa(i),
-							Word:  []rune(result.Body),
-							Value: result.Value,
-						},
-					},
-				})
-				i++
-			}
-			toks = append(toks, timeNodes...)
-		}
+	//we have exhausted the token list now we will append
+	//the remianing time results to the tokens list
+	timeNodes := []Token{}
+	for i < len(validResults) {
+		result := validResults[i]
+		timeNodes = append(timeNodes, Token{
+			Pos:  result.Start,
+			Word: []rune(result.Body),
+			Nodes: []Node{
+				&TimeNode{
+					UID:   "T" + strconv.Itoa(i),
+					Word:  []rune(result.Body),
+					Value: result.Value,
+				},
+			},
+		})
+		i++
 	}
+	toks = append(toks, timeNodes...)
 
 	return toks
 }
@@ -5,10 +5,15 @@
 package interpreter_test
 
 import (
+	"context"
+	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/cuttle-ai/octopus/datetime"
 	"github.com/cuttle-ai/octopus/interpreter"
+	"github.com/cuttle-ai/octopus/testutils"
 )
 
 /*
@@ -26,3 +31,294 @@ func TestTokenize(t *testing.T) {
 		t.Error("Expected to have more than 1 tokens found. Go none")
 	}
 }
+
+func TestTokenizeWithOptions(t *testing.T) {
+	loadTestDICT()
+	time.Sleep(time.Second)
+	ref := time.Date(2019, time.May, 21, 10, 30, 0, 0, time.UTC)
+	toks, err := interpreter.TokenizeWithOptions(testUser, []rune("show me the Swift cars sold yesterday"), datetime.Options{RefTime: ref, Timezone: "UTC"})
+	if err != nil {
+		t.Fatal("error while tokenizing the sentence", err)
+	}
+	for _, tok := range toks {
+		if len(tok.Times) == 0 {
+			continue
+		}
+		from, _ := tok.Times[0].Value.Bounds()
+		if expected := time.Date(2019, time.May, 20, 0, 0, 0, 0, time.UTC); from == nil || !from.Equal(expected) {
+			t.Error("Expected yesterday to be resolved against the reference time", expected, "got", from)
+		}
+		return
+	}
+	t.Error("Expected a time token for yesterday. Got none")
+}
+
+func TestTokenizeContext(t *testing.T) {
+	loadTestDICT()
+	time.Sleep(time.Second)
+
+	//dictionary doesn't exist
+	_, err := interpreter.TokenizeContext(context.Background(), "missing-user", []rune("show me the Swift cars"))
+	if err != interpreter.ErrDictionaryNotFound {
+		t.Error("Expected the dictionary not found error. Got", err)
+	}
+
+	//deadline has already exceeded
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	time.Sleep(time.Millisecond)
+	_, err = interpreter.TokenizeContext(ctx, testUser, []rune("show me the Swift cars"))
+	if err != interpreter.ErrTimeout {
+		t.Error("Expected the timeout error. Got", err)
+	}
+
+	//context is cancelled
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = interpreter.TokenizeContext(ctx, testUser, []rune("show me the Swift cars"))
+	if err != context.Canceled {
+		t.Error("Expected the context cancelled error. Got", err)
+	}
+}
+
+//silentService is a datetime service which never responds
+type silentService struct{}
+
+func (s silentService) Query(ctx context.Context, query []rune, opts datetime.Options) chan datetime.Results {
+	return make(chan datetime.Results)
+}
+
+func TestStartCheckingForDatesContext(t *testing.T) {
+	ser, _ := datetime.DefaultService()
+	datetime.SetDefaultService(silentService{})
+	defer datetime.SetDefaultService(ser)
+
+	//waiting for the service to merge the fiscal periods is given up with the context
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := interpreter.StartCheckingForDates(ctx, []rune("sales in FY19"), datetime.Options{})
+	if err != nil {
+		t.Fatal("error while checking for the dates", err)
+	}
+	cancel()
+	select {
+	case res := <-ch:
+		if res.Err != context.Canceled {
+			t.Error("Expected the context cancelled error. Got", res.Err)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected the dates to be given up once the context is cancelled")
+	}
+}
+
+//boundaryDICT returns a dictionary having words which are also substrings of common words
+func boundaryDICT() interpreter.DICT {
+	table := &interpreter.TableNode{UID: "boundary", Name: "boundary"}
+	car := &interpreter.ColumnNode{UID: "car", PUID: table.UID, PN: table, Word: []rune("car"), Name: "car"}
+	hindiCar := &interpreter.ColumnNode{UID: "hindi-car", PUID: table.UID, PN: table, Word: []rune("कार"), Name: "कार"}
+	is := &interpreter.OperatorNode{UID: "is", Word: []rune("is"), Operation: interpreter.EqOperator}
+	greater := &interpreter.OperatorNode{UID: "greater", Word: []rune(">="), Operation: interpreter.GreaterOperator}
+	return interpreter.DICT{Map: map[string]interpreter.Token{
+		"car": {Word: []rune("car"), Nodes: []interpreter.Node{car}},
+		"कार": {Word: []rune("कार"), Nodes: []interpreter.Node{hindiCar}},
+		"is":  {Word: []rune("is"), Nodes: []interpreter.Node{is}},
+		">=":  {Word: []rune(">="), Nodes: []interpreter.Node{greater}},
+	}}
+}
+
+type wordBoundaryTest struct {
+	testutils.Test
+	Input        string
+	WordBoundary bool
+	Columns      int
+	Operators    int
+}
+
+var wordBoundaryTestcases = []wordBoundaryTest{
+	{
+		Test:  testutils.Test{Name: "Substrings", Description: "words inside other words are not matched"},
+		Input: "this scary film", WordBoundary: true,
+	},
+	{
+		Test:  testutils.Test{Name: "Whole words", Description: "words separated by spaces and punctuations are matched"},
+		Input: "car is (swift)", WordBoundary: true, Columns: 1, Operators: 1,
+	},
+	{
+		Test:  testutils.Test{Name: "Symbols", Description: "symbol operators are matched next to the words"},
+		Input: "car>=100", WordBoundary: true, Columns: 1, Operators: 1,
+	},
+	{
+		Test:  testutils.Test{Name: "Combining marks", Description: "words followed by combining marks of the script are not matched"},
+		Input: "कारें", WordBoundary: true,
+	},
+	{
+		Test:  testutils.Test{Name: "Unicode words", Description: "unicode words are matched at their boundaries"},
+		Input: "कार की बिक्री", WordBoundary: true, Columns: 1,
+	},
+	{
+		Test:  testutils.Test{Name: "Without boundaries", Description: "substrings are matched when word boundaries are disabled"},
+		Input: "this scary film", Columns: 1, Operators: 1,
+	},
+}
+
+func TestTokenizeWordBoundary(t *testing.T) {
+	if err := interpreter.AddDICT("boundary-user", boundaryDICT()); err != nil {
+		t.Fatal("error while adding the dictionary", err)
+	}
+	defer interpreter.SetTokenizerConfig(interpreter.DefaultTokenizerConfig)
+	for _, v := range wordBoundaryTestcases {
+		t.Run(v.Name, func(t *testing.T) {
+			interpreter.SetTokenizerConfig(interpreter.TokenizerConfig{WordBoundary: v.WordBoundary})
+			toks, err := interpreter.TokenizeContext(context.Background(), "boundary-user", []rune(v.Input))
+			if err != nil {
+				t.Fatal("error while tokenizing the sentence", err)
+			}
+			columns, operators := 0, 0
+			for _, tok := range toks {
+				columns += len(tok.Columns)
+				operators += len(tok.Operators)
+			}
+			if columns != v.Columns || operators != v.Operators {
+				t.Error("Expected", v.Columns, "columns and", v.Operators, "operators. Got", columns, "columns and", operators, "operators in", toks)
+			}
+		})
+	}
+}
+
+//overlapToken returns a token of the word at the position having the node
+func overlapToken(pos int, word string, n interpreter.Node) interpreter.Token {
+	return interpreter.Token{Pos: pos, Word: []rune(word), Nodes: []interpreter.Node{n}}
+}
+
+type resolveOverlapsTest struct {
+	testutils.Test
+	Input     []interpreter.Token
+	Priority  []interpreter.Type
+	Expected  []string
+	Discarded []string
+}
+
+var overlapTable = &interpreter.TableNode{UID: "automobile-sales", Name: "automobile sales"}
+var overlapColumn = &interpreter.ColumnNode{UID: "sales", PN: overlapTable, Name: "sales"}
+var overlapValue = &interpreter.ValueNode{UID: "sales-value", PN: overlapColumn, Name: "sales"}
+
+var resolveOverlapsTestcases = []resolveOverlapsTest{
+	{
+		Test:     testutils.Test{Name: "Longest match", Description: "longest of the overlapping matches is preferred"},
+		Input:    []interpreter.Token{overlapToken(11, "sales", overlapColumn), overlapToken(0, "automobile sales", overlapTable)},
+		Priority: interpreter.DefaultNodePriority,
+		Expected: []string{"automobile sales"}, Discarded: []string{"sales"},
+	},
+	{
+		Test:     testutils.Test{Name: "Non overlapping", Description: "non overlapping matches are sorted by their position"},
+		Input:    []interpreter.Token{overlapToken(6, "sales", overlapColumn), overlapToken(0, "brand", overlapColumn)},
+		Priority: interpreter.DefaultNodePriority,
+		Expected: []string{"brand", "sales"},
+	},
+	{
+		Test:     testutils.Test{Name: "Node priority", Description: "node type with higher priority is preferred among the matches of same length"},
+		Input:    []interpreter.Token{overlapToken(3, "sales", overlapValue), overlapToken(0, "carsa", overlapColumn)},
+		Priority: []interpreter.Type{interpreter.Value, interpreter.Column},
+		Expected: []string{"sales"}, Discarded: []string{"carsa"},
+	},
+	{
+		Test:     testutils.Test{Name: "Position", Description: "first match is preferred among the matches of same length and priority"},
+		Input:    []interpreter.Token{overlapToken(3, "sales", overlapColumn), overlapToken(0, "carsa", overlapColumn)},
+		Priority: interpreter.DefaultNodePriority,
+		Expected: []string{"carsa"}, Discarded: []string{"sales"},
+	},
+}
+
+func TestResolveOverlaps(t *testing.T) {
+	for _, v := range resolveOverlapsTestcases {
+		t.Run(v.Name, func(t *testing.T) {
+			toks := interpreter.ResolveOverlaps(v.Input, v.Priority)
+			words, discarded := []string{}, []string{}
+			for _, tok := range toks {
+				words = append(words, string(tok.Word))
+				for _, d := range tok.Discarded {
+					discarded = append(discarded, string(d.Word))
+				}
+			}
+			if strings.Join(words, ",") != strings.Join(v.Expected, ",") || strings.Join(discarded, ",") != strings.Join(v.Discarded, ",") {
+				t.Error("Expected", v.Expected, "with discarded", v.Discarded, "got", words, "with discarded", discarded)
+			}
+		})
+	}
+}
+
+func TestTokenizeOverlaps(t *testing.T) {
+	table := &interpreter.TableNode{UID: "automobile-sales", Name: "automobile sales"}
+	sales := &interpreter.ColumnNode{UID: "sales", PUID: table.UID, PN: table, Name: "sales"}
+	fy := &interpreter.ColumnNode{UID: "financial-year", PUID: table.UID, PN: table, Name: "financial-year"}
+	financial := &interpreter.ValueNode{UID: "financial", PUID: sales.UID, PN: sales, Name: "financial"}
+	err := interpreter.AddDICT("overlap-user", interpreter.DICT{Map: map[string]interpreter.Token{
+		"automobile sales": {Word: []rune("automobile sales"), Nodes: []interpreter.Node{table}},
+		"sales":            {Word: []rune("sales"), Nodes: []interpreter.Node{sales}},
+		"financial-year":   {Word: []rune("financial-year"), Nodes: []interpreter.Node{fy}},
+		"financial":        {Word: []rune("financial"), Nodes: []interpreter.Node{financial}},
+	}})
+	if err != nil {
+		t.Fatal("error while adding the dictionary", err)
+	}
+	toks, err := interpreter.TokenizeContext(context.Background(), "overlap-user", []rune("automobile sales by financial-year"))
+	if err != nil {
+		t.Fatal("error while tokenizing the sentence", err)
+	}
+	if len(toks) != 3 || len(toks[0].Tables) != 1 || len(toks[1].Unknowns) != 1 || len(toks[2].Columns) != 1 {
+		t.Fatal("Expected the table, an unknown and the column. Got", toks)
+	}
+	if len(toks[0].Discarded) != 1 || len(toks[0].Discarded[0].Columns) != 1 {
+		t.Error("Expected the sales column to be discarded in favour of the table. Got", toks[0].Discarded)
+	}
+	if len(toks[2].Discarded) != 1 || len(toks[2].Discarded[0].Values) != 1 {
+		t.Error("Expected the financial value to be discarded in favour of the column. Got", toks[2].Discarded)
+	}
+}
+
+func TestTokenizeAliases(t *testing.T) {
+	table := &interpreter.TableNode{UID: "alias", Name: "automobile sales", Aliases: []string{"car sales"}}
+	sales := &interpreter.ColumnNode{UID: "alias-sales", PUID: table.UID, PN: table, Name: "sales", Aliases: []string{"revenue", "turnover"}}
+	brand := &interpreter.ColumnNode{UID: "alias-brand", PUID: table.UID, PN: table, Name: "brand"}
+	swift := &interpreter.ValueNode{UID: "alias-swift", PUID: brand.UID, PN: brand, Name: "Swift", Aliases: []string{"maruti swift"}}
+	err := interpreter.AddDICT("alias-user", interpreter.DICT{Map: map[string]interpreter.Token{
+		"automobile sales": {Word: []rune("automobile sales"), Nodes: []interpreter.Node{table}},
+		"sales":            {Word: []rune("sales"), Nodes: []interpreter.Node{sales}},
+		"brand":            {Word: []rune("brand"), Nodes: []interpreter.Node{brand}},
+		"swift":            {Word: []rune("swift"), Nodes: []interpreter.Node{swift}},
+	}})
+	if err != nil {
+		t.Fatal("error while adding the dictionary", err)
+	}
+	toks, err := interpreter.TokenizeContext(context.Background(), "alias-user", []rune("Revenue of Maruti Swift in car sales vs sales"))
+	if err != nil {
+		t.Fatal("error while tokenizing the sentence", err)
+	}
+	if len(toks) != 7 {
+		t.Fatal("Expected 7 tokens. Got", toks)
+	}
+	if len(toks[0].Columns) != 1 || toks[0].Columns[0].UID != sales.UID || toks[0].Columns[0].MatchedAlias != "revenue" {
+		t.Error("Expected the sales column matched with the alias revenue. Got", toks[0].Columns)
+	}
+	if len(toks[2].Values) != 1 || toks[2].Values[0].UID != swift.UID || toks[2].Values[0].MatchedAlias != "maruti swift" {
+		t.Error("Expected the swift value matched with the alias maruti swift. Got", toks[2].Values)
+	}
+	if len(toks[4].Tables) != 1 || toks[4].Tables[0].UID != table.UID || toks[4].Tables[0].MatchedAlias != "car sales" {
+		t.Error("Expected the table matched with the alias car sales. Got", toks[4].Tables)
+	}
+	if len(toks[6].Columns) != 1 || toks[6].Columns[0].MatchedAlias != "" {
+		t.Error("Expected the sales column matched with its word. Got", toks[6].Columns)
+	}
+	if sales.MatchedAlias != "" {
+		t.Error("Expected the node in the dictionary not to be modified. Got", sales.MatchedAlias)
+	}
+
+	//matched alias is reported in the output
+	b, err := json.Marshal(&toks[0].Columns[0])
+	if err != nil || !strings.Contains(string(b), `"matched_alias":"revenue"`) {
+		t.Error("Expected the matched alias in the json of the column. Got", string(b), err)
+	}
+	decoded := interpreter.ColumnNode{}
+	if err := json.Unmarshal(b, &decoded); err != nil || len(decoded.Aliases) != 2 || decoded.MatchedAlias != "revenue" {
+		t.Error("Expected the aliases to be decoded from the json. Got", decoded, err)
+	}
+}
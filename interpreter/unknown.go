@@ -24,6 +24,8 @@ type UnknownNode struct {
 	PN Node
 	//Resolved indicates that the node is resolved
 	Resolved bool
+	//Literal indicates that the word was quoted in the sentence. Literals are bound exactly as they are in the filters
+	Literal bool
 }
 
 type unknownNode struct {
@@ -32,6 +34,7 @@ type unknownNode struct {
 	PUID     string `json:"puid,omitempty"`
 	Resolved bool   `json:"resolved,omitempty"`
 	Type     string `json:"type,omitempty"`
+	Literal  bool   `json:"literal,omitempty"`
 }
 
 //Copy will return a copy of the node
@@ -42,6 +45,7 @@ func (u *UnknownNode) Copy() Node {
 		PN:       u.PN,
 		PUID:     u.PUID,
 		Resolved: u.Resolved,
+		Literal:  u.Literal,
 	}
 }
 
@@ -73,7 +77,7 @@ func (u *UnknownNode) Parent() Node {
 //MarshalJSON encodes the node into a serializable json
 func (u *UnknownNode) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&unknownNode{
-		u.UID, string(u.Word), u.PUID, u.Resolved, "Unknown",
+		u.UID, string(u.Word), u.PUID, u.Resolved, "Unknown", u.Literal,
 	})
 }
 
@@ -88,6 +92,7 @@ func (u *UnknownNode) UnmarshalJSON(data []byte) error {
 	u.Word = []rune(m.Word)
 	u.PUID = m.PUID
 	u.Resolved = m.Resolved
+	u.Literal = m.Literal
 	return nil
 }
 
@@ -25,26 +25,34 @@ type ValueNode struct {
 	Name string
 	//Resolved indicates that the node is resolved
 	Resolved bool
+	//Aliases are the other words with which the value node can be matched
+	Aliases []string
+	//MatchedAlias is the alias with which the node was matched in the sentence. It is empty if the node was matched with its word
+	MatchedAlias string
 }
 
 type valueNode struct {
-	UID      string `json:"uid,omitempty"`
-	Word     string `json:"word,omitempty"`
-	PUID     string `json:"puid,omitempty"`
-	Name     string `json:"name,omitempty"`
-	Resolved bool   `json:"resolved,omitempty"`
-	Type     string `json:"type,omitempty"`
+	UID          string   `json:"uid,omitempty"`
+	Word         string   `json:"word,omitempty"`
+	PUID         string   `json:"puid,omitempty"`
+	Name         string   `json:"name,omitempty"`
+	Resolved     bool     `json:"resolved,omitempty"`
+	Type         string   `json:"type,omitempty"`
+	Aliases      []string `json:"aliases,omitempty"`
+	MatchedAlias string   `json:"matched_alias,omitempty"`
 }
 
 //Copy will return a copy of the node
 func (v *ValueNode) Copy() Node {
 	return &ValueNode{
-		UID:      v.UID,
-		Word:     v.Word,
-		PN:       v.PN,
-		PUID:     v.PUID,
-		Name:     v.Name,
-		Resolved: v.Resolved,
+		UID:          v.UID,
+		Word:         v.Word,
+		PN:           v.PN,
+		PUID:         v.PUID,
+		Name:         v.Name,
+		Resolved:     v.Resolved,
+		Aliases:      v.Aliases,
+		MatchedAlias: v.MatchedAlias,
 	}
 }
 
@@ -76,7 +84,7 @@ func (v *ValueNode) Parent() Node {
 //MarshalJSON encodes the node into a serializable json
 func (v *ValueNode) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&valueNode{
-		v.UID, string(v.Word), v.PUID, v.Name, v.Resolved, "Value",
+		v.UID, string(v.Word), v.PUID, v.Name, v.Resolved, "Value", v.Aliases, v.MatchedAlias,
 	})
 }
 
@@ -92,6 +100,8 @@ func (v *ValueNode) UnmarshalJSON(data []byte) error {
 	v.PUID = m.PUID
 	v.Name = m.Name
 	v.Resolved = m.Resolved
+	v.Aliases = m.Aliases
+	v.MatchedAlias = m.MatchedAlias
 	return nil
 }
 
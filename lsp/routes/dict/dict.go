@@ -9,13 +9,14 @@ import (
 	"context"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/cuttle-ai/octopus/interpreter"
 	"github.com/cuttle-ai/octopus/lsp/routes"
 	"github.com/cuttle-ai/octopus/lsp/routes/response"
 )
 
-var testCollection = &interpreter.TableNode{UID: "automobile-sales", Word: []rune("Automobile sales")}
+var testCollection = &interpreter.TableNode{UID: "automobile-sales", Word: []rune("Automobile sales"), FiscalYearStart: time.April}
 
 var testColumn = &interpreter.ColumnNode{UID: "car", PUID: "automobile-sales", PN: testCollection, Word: []rune("car"), DataType: interpreter.DataTypeString}
 
@@ -27,6 +28,8 @@ var testColumn3 = &interpreter.ColumnNode{UID: "sales", PUID: "automobile-sales"
 
 var testValue = &interpreter.ValueNode{UID: "swift", Word: []rune("Swift"), PN: testColumn, PUID: "car"}
 
+var testValue1 = &interpreter.ValueNode{UID: "alto", Word: []rune("Alto"), PN: testColumn, PUID: "car"}
+
 var testOperator = &interpreter.OperatorNode{UID: "equal-is", Word: []rune("is"), Operation: interpreter.EqOperator}
 
 var notEqOperator = &interpreter.OperatorNode{UID: "not-equal", Word: []rune("not"), Operation: interpreter.NotEqOperator}
@@ -35,6 +38,14 @@ var greaterThanOperator = &interpreter.OperatorNode{UID: "greater-than", Word: [
 
 var lessThanOperator = &interpreter.OperatorNode{UID: "less-than", Word: []rune("<="), Operation: interpreter.GreaterOperator}
 
+var betweenOperator = &interpreter.OperatorNode{UID: "between", Word: []rune("between"), Operation: interpreter.BetweenOperator}
+
+var topRank = &interpreter.RankNode{UID: "top", Word: []rune("top"), Descending: true}
+
+var highestRank = &interpreter.RankNode{UID: "highest", Word: []rune("highest"), Descending: true, Limit: 1}
+
+var lowestRank = &interpreter.RankNode{UID: "lowest", Word: []rune("lowest"), Limit: 1}
+
 func init() {
 	testCollection.DefaultDateField = testColumn2
 	testCollection.DefaultDateFieldUID = testColumn2.UID
@@ -62,6 +73,10 @@ var testTokens = map[string]interpreter.Token{
 		Word:  []rune("Swift"),
 		Nodes: []interpreter.Node{testValue},
 	},
+	"Alto": {
+		Word:  []rune("Alto"),
+		Nodes: []interpreter.Node{testValue1},
+	},
 	"is": {
 		Word:  []rune("is"),
 		Nodes: []interpreter.Node{testOperator},
@@ -82,6 +97,22 @@ var testTokens = map[string]interpreter.Token{
 		Word:  []rune(">"),
 		Nodes: []interpreter.Node{greaterThanOperator},
 	},
+	"between": {
+		Word:  []rune("between"),
+		Nodes: []interpreter.Node{betweenOperator},
+	},
+	"top": {
+		Word:  []rune("top"),
+		Nodes: []interpreter.Node{topRank},
+	},
+	"highest": {
+		Word:  []rune("highest"),
+		Nodes: []interpreter.Node{highestRank},
+	},
+	"lowest": {
+		Word:  []rune("lowest"),
+		Nodes: []interpreter.Node{lowestRank},
+	},
 }
 
 var testDICT = interpreter.DICT{Map: testTokens}
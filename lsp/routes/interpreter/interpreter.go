@@ -9,7 +9,9 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"time"
 
+	"github.com/cuttle-ai/octopus/datetime"
 	"github.com/cuttle-ai/octopus/interpreter"
 	"github.com/cuttle-ai/octopus/lsp/routes"
 	"github.com/cuttle-ai/octopus/lsp/routes/dict"
@@ -20,31 +22,93 @@ import (
 type Query struct {
 	//NL is the natural language query
 	NL string `json:"nl,omitempty"`
+	//RefTime is the reference time against which the relative dates in the query are resolved. Current time by default
+	RefTime *time.Time `json:"ref_time,omitempty"`
+	//Locale of the query like en_US
+	Locale string `json:"locale,omitempty"`
+	//Timezone is the IANA timezone in which the dates in the query are resolved like Asia/Kolkata
+	Timezone string `json:"timezone,omitempty"`
+	//N is the maximum no. of candidate interpretations to be returned. DefaultCandidates by default
+	N int `json:"n,omitempty"`
+}
+
+//DefaultCandidates is the default no. of candidate interpretations returned
+const DefaultCandidates = 5
+
+//Result is the result of interpreting a natural language query.
+//It has the best interpretation of the query along with the ranked candidate interpretations
+type Result struct {
+	*interpreter.Query
+	//Candidates are the candidate interpretations of the query ranked by their score. The first candidate is the best interpretation
+	Candidates []interpreter.Interpretation `json:"candidates,omitempty"`
 }
 
 //Interpret will interpret a given natural language query
 func Interpret(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	rq, toks, ok := tokenize(ctx, w, r)
+	if !ok {
+		return
+	}
+	n := rq.N
+	if n <= 0 {
+		n = DefaultCandidates
+	}
+	ins, err := interpreter.InterpretNContext(ctx, toks, n)
+	if err != nil {
+		//error while interpreting the user query
+		response.WriteError(w, response.Error{Err: err.Error()}, errorStatus(err))
+		return
+	}
+	response.Write(w, Result{Query: &ins[0].Query, Candidates: ins})
+}
+
+//Explain will interpret a given natural language query and explain the rules applied to interpret it
+func Explain(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	_, toks, ok := tokenize(ctx, w, r)
+	if !ok {
+		return
+	}
+	ex, err := interpreter.InterpretExplainContext(ctx, toks)
+	if err != nil {
+		//error while interpreting the user query
+		response.WriteError(w, response.Error{Err: err.Error()}, errorStatus(err))
+		return
+	}
+	response.Write(w, ex)
+}
+
+//tokenize decodes the query in the request and tokenizes it. If there is an error, it is written to the response and false is returned
+func tokenize(ctx context.Context, w http.ResponseWriter, r *http.Request) (*Query, []interpreter.FastToken, bool) {
 	rq := &Query{}
 	dec := json.NewDecoder(r.Body)
 	err := dec.Decode(rq)
 	if err != nil {
 		//error while decoding the request param
 		response.WriteError(w, response.Error{Err: err.Error()}, http.StatusBadRequest)
-		return
+		return nil, nil, false
 	}
-	toks, err := interpreter.Tokenize(dict.TestUser, []rune(rq.NL))
+	opts := datetime.Options{Locale: rq.Locale, Timezone: rq.Timezone}
+	if rq.RefTime != nil {
+		opts.RefTime = *rq.RefTime
+	}
+	toks, err := interpreter.TokenizeContextWithOptions(ctx, dict.TestUser, []rune(rq.NL), opts)
 	if err != nil {
 		//error while tokenizing the user query
-		response.WriteError(w, response.Error{Err: err.Error()}, http.StatusBadRequest)
-		return
+		response.WriteError(w, response.Error{Err: err.Error()}, errorStatus(err))
+		return nil, nil, false
 	}
-	ins, err := interpreter.Interpret(toks)
-	if err != nil {
-		//error while interpreting the user query
-		response.WriteError(w, response.Error{Err: err.Error()}, http.StatusBadRequest)
-		return
+	return rq, toks, true
+}
+
+//errorStatus returns the http status for the error while interpreting the query
+func errorStatus(err error) int {
+	switch err {
+	case interpreter.ErrTimeout:
+		return http.StatusGatewayTimeout
+	case interpreter.ErrDictionaryNotFound:
+		return http.StatusNotFound
 	}
-	response.Write(w, ins)
+	return http.StatusBadRequest
 }
 
 func init() {
@@ -54,5 +118,10 @@ func init() {
 			Pattern:     "/interpret",
 			HandlerFunc: Interpret,
 		},
+		routes.Route{
+			Version:     "v1",
+			Pattern:     "/interpret/explain",
+			HandlerFunc: Explain,
+		},
 	)
 }
@@ -45,3 +45,31 @@ var AtleastOneColumnFromValue = interpreter.Rule{
 		return qu, nil
 	},
 }
+
+//AtleastOneColumnFromNumber will check whether the query has atleast one select if none available and if the number is part of a filter, will select the column of the filter
+var AtleastOneColumnFromNumber = interpreter.Rule{
+	Name:        "Atleast one select with borrow from number",
+	Description: "This will check whether the query has atleast one select if none available and if the number in the template <number> is part of a filter like price from 10 to 20, will select the column of the filter",
+	Template:    []interpreter.Type{interpreter.Number},
+	Resolve: func(qu interpreter.Query, toks []interpreter.FastToken, index int) (interpreter.Query, error) {
+		/*
+		 * If the query doesn't have any select fields we will find the filter having the number
+		 * If found, we will add its column to the select
+		 */
+		if len(qu.Select) != 0 || index >= len(toks) || len(toks[index].Numbers) == 0 || !toks[index].Numbers[0].IsResolved() {
+			return qu, nil
+		}
+		uid := toks[index].Numbers[0].UID
+		for _, f := range qu.Filters {
+			if f.Column == nil || !f.IsResolved() {
+				continue
+			}
+			if (f.Number != nil && f.Number.UID == uid) || (f.ToNumber != nil && f.ToNumber.UID == uid) {
+				qu.Select = []interpreter.ColumnNode{*f.Column}
+				break
+			}
+		}
+
+		return qu, nil
+	},
+}
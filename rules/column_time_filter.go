@@ -34,6 +34,7 @@ var ColumnTimeFilter = interpreter.Rule{
 		toks[index].Columns[0].SetResolved(true)
 		toks[index+1].Operators[0].SetResolved(true)
 		toks[index+2].Times[0].SetResolved(true)
+		resolveFiscal(&toks[index+2].Times[0], toks[index].Columns[0].PN)
 		toks[index+1].Operators[0].Column = &toks[index].Columns[0]
 		toks[index+1].Operators[0].Time = &toks[index+2].Times[0]
 		if len(qu.Filters) == 0 {
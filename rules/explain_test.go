@@ -0,0 +1,100 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package rules
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cuttle-ai/octopus/datetime"
+	"github.com/cuttle-ai/octopus/interpreter"
+)
+
+/*
+ * This file contains the snapshot tests for the explanation of the default rules
+ */
+
+//update if true, the snapshots are updated with the explanations
+var update = flag.Bool("update", false, "update the snapshots of the explanations")
+
+//explainTokens returns the tokens of sales of Swift before 2019 tokenized with the offline datetime service.
+//before is not in the dictionary, so before 2019 is left to the datetime service as a time till 2019
+func explainTokens(t *testing.T) []interpreter.FastToken {
+	table := &interpreter.TableNode{UID: "automobile-sales", Name: "automobile sales", DefaultDateFieldUID: "sold-on"}
+	table.DefaultDateField = &interpreter.ColumnNode{UID: "sold-on", PUID: table.UID, PN: table, Name: "sold on", DataType: interpreter.DataTypeDate}
+	sales := &interpreter.ColumnNode{UID: "sales", PUID: table.UID, PN: table, Word: []rune("sales"), Name: "sales", Measure: true, DataType: interpreter.DataTypeInt}
+	car := &interpreter.ColumnNode{UID: "car", PUID: table.UID, PN: table, Word: []rune("car"), Name: "car", Dimension: true, DataType: interpreter.DataTypeString}
+	swift := &interpreter.ValueNode{UID: "swift", PUID: car.UID, PN: car, Word: []rune("Swift"), Name: "Swift"}
+	err := interpreter.AddDICT("explain-user", interpreter.DICT{Map: map[string]interpreter.Token{
+		"sales": {Word: []rune("sales"), Nodes: []interpreter.Node{sales}},
+		"swift": {Word: []rune("swift"), Nodes: []interpreter.Node{swift}},
+	}})
+	if err != nil {
+		t.Fatal("error while adding the dictionary", err)
+	}
+	ref := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	toks, err := interpreter.TokenizeContextWithOptions(context.Background(), "explain-user", []rune("sales of Swift before 2019"), datetime.Options{RefTime: ref, Timezone: "UTC"})
+	if err != nil {
+		t.Fatal("error while tokenizing the sentence", err)
+	}
+	return toks
+}
+
+func TestInterpretExplain(t *testing.T) {
+	LoadDefaultRules()
+	ser, _ := datetime.DefaultService()
+	datetime.SetDefaultService(datetime.NewOffline())
+	defer datetime.SetDefaultService(ser)
+	ex, err := interpreter.InterpretExplain(explainTokens(t))
+	if err != nil {
+		t.Fatal("error while explaining the tokens", err)
+	}
+	got, err := json.MarshalIndent(ex, "", "  ")
+	if err != nil {
+		t.Fatal("error while encoding the explanation", err)
+	}
+
+	//the explanation is same across the runs
+	again, err := interpreter.InterpretExplain(explainTokens(t))
+	if err != nil {
+		t.Fatal("error while explaining the tokens", err)
+	}
+	if b, _ := json.MarshalIndent(again, "", "  "); !bytes.Equal(got, b) {
+		t.Fatal("Expected the explanation to be stable across the runs")
+	}
+
+	//before 2019 is a filter till the start of 2019
+	s, err := ex.Query.ToSQL()
+	if err != nil {
+		t.Fatal("error while converting the query to sql", err)
+	}
+	if expected := `SELECT "sales" FROM "automobile sales" WHERE "car" = $1 AND "sold on" < $2`; s.Query != expected {
+		t.Error("Expected query", "`"+expected+"`", "got", "`"+s.Query+"`")
+	}
+	if till, ok := s.Args[len(s.Args)-1].(time.Time); len(s.Args) != 2 || !ok || !till.Equal(time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("Expected the filter to be till the start of 2019. Got", s.Args)
+	}
+
+	//comparing with the snapshot
+	snapshot := filepath.Join("testdata", "explain_sales_of_swift_before_2019.json")
+	if *update {
+		if err := ioutil.WriteFile(snapshot, append(got, '\n'), 0644); err != nil {
+			t.Fatal("error while updating the snapshot", err)
+		}
+	}
+	expected, err := ioutil.ReadFile(snapshot)
+	if err != nil {
+		t.Fatal("error while reading the snapshot", err)
+	}
+	if !bytes.Equal(bytes.TrimSpace(expected), got) {
+		t.Error("Expected the explanation to match the snapshot", snapshot, "got", string(got))
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package rules
+
+import "github.com/cuttle-ai/octopus/interpreter"
+
+/*
+ * This file contains the rule defnition for moving the filters on measures to having filters in a grouped query
+ */
+
+//HavingFilter will move the filters applied on the measures to having filters if the query is grouped by a dimension
+var HavingFilter = interpreter.Rule{
+	Name: "Having filter on measures",
+	Description: "This rule will check whether the query is grouped by a dimension. If yes, the filters and the filter groups applied on the measures are moved to having filters so that they are applied on the aggregated measures. " +
+		"A query selecting only the dimensions is grouped by them. The rule works on the whole query, so it has to be run after the rules adding the filters and the select fields.",
+	Template: []interpreter.Type{interpreter.Column},
+	Resolve: func(qu interpreter.Query, toks []interpreter.FastToken, index int) (interpreter.Query, error) {
+		/*
+		 * We will route the filters on the measures of the query
+		 * Routing is done only once as the filters are not on the measures after routing
+		 */
+		qu.RouteMeasureFilters()
+
+		return qu, nil
+	},
+}
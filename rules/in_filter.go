@@ -0,0 +1,142 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package rules
+
+import (
+	"strings"
+
+	"github.com/cuttle-ai/octopus/interpreter"
+)
+
+/*
+ * This file contains the rule defnition for collapsing the values of the same column joined by or/commas to an in filter
+ */
+
+//valueConnectors are the words with which the values of a column can be joined to form a list
+var valueConnectors = map[string]bool{
+	"or":   true,
+	",":    true,
+	", or": true,
+}
+
+//InFilter will collapse the values of the same column found in the template <value> <unknown> <value> to an in filter
+//where the unknown is a connector like or, comma
+var InFilter = interpreter.Rule{
+	Name: "In filter with values joined by or/commas",
+	Description: "This rule will find the list of values of a column in the query. It will assign an in filter if found in the template <value> <unknown> <value> where unknown is or/comma. " +
+		"If the list is preceded by <field> <operator>, they are also considered for the filter with not operator negating the filter",
+	Template: []interpreter.Type{interpreter.Value, interpreter.Unknown, interpreter.Value},
+	Resolve: func(qu interpreter.Query, toks []interpreter.FastToken, index int) (interpreter.Query, error) {
+		/*
+		 * We will check whether the values belong to the same column and the unknown is a connector
+		 * If an in filter already exists with the first value, the second value is added to it
+		 * Else we will create a new in filter with both the values
+		 * If the values are preceded by the column and an operator, we will resolve them too
+		 */
+		if index+2 >= len(toks) || len(toks[index].Values) == 0 || len(toks[index+1].Unknowns) == 0 || len(toks[index+2].Values) == 0 {
+			//we don't have enough the tokens for the given index
+			return qu, nil
+		}
+		first, connector, second := &toks[index].Values[0], &toks[index+1].Unknowns[0], &toks[index+2].Values[0]
+		if connector.IsResolved() || second.IsResolved() {
+			//the connector or the second value is already resolved
+			return qu, nil
+		}
+		if !valueConnectors[strings.ToLower(strings.TrimSpace(string(connector.Word)))] {
+			//the unknown is not a connector
+			return qu, nil
+		}
+		if first.PUID != second.PUID || first.PN == nil {
+			//the values doesn't belong to the same column
+			return qu, nil
+		}
+		if len(first.Name) == 0 || len(second.Name) == 0 {
+			//the values doesn't have the names to be bound
+			return qu, nil
+		}
+
+		//if an in filter exists with the first value we will add the second value to it
+		if existing := findInFilter(&qu, first.UID); existing != nil {
+			connector.SetResolved(true)
+			second.SetResolved(true)
+			existing.Values = append(existing.Values, *second)
+			return qu, nil
+		}
+		if first.IsResolved() {
+			//the first value is part of some other filter
+			return qu, nil
+		}
+
+		//creating a new in filter
+		first.SetResolved(true)
+		connector.SetResolved(true)
+		second.SetResolved(true)
+		operator := interpreter.OperatorNode{
+			UID:       "Operator-" + first.UID,
+			Word:      []rune("in"),
+			Resolved:  true,
+			Column:    first.PN,
+			Values:    []interpreter.ValueNode{*first, *second},
+			Operation: interpreter.InOperator,
+		}
+
+		//checking whether the values are preceded by the column and an operator
+		negate := false
+		if index-2 >= 0 && len(toks[index-2].Columns) > 0 && len(toks[index-1].Operators) > 0 &&
+			!toks[index-2].Columns[0].IsResolved() && !toks[index-1].Operators[0].IsResolved() &&
+			toks[index-2].Columns[0].UID == first.PUID &&
+			(toks[index-1].Operators[0].Operation == interpreter.EqOperator || toks[index-1].Operators[0].Operation == interpreter.NotEqOperator) {
+			toks[index-2].Columns[0].SetResolved(true)
+			toks[index-1].Operators[0].SetResolved(true)
+			operator.Column = &toks[index-2].Columns[0]
+			negate = toks[index-1].Operators[0].Operation == interpreter.NotEqOperator
+		}
+
+		if negate {
+			qu.FilterGroups = append(qu.FilterGroups, interpreter.FilterGroup{
+				Logic:   interpreter.NotLogic,
+				Filters: []interpreter.OperatorNode{operator},
+			})
+		} else {
+			qu.Filters = append(qu.Filters, operator)
+		}
+		if operator.Column.PN != nil {
+			qu.Tables[operator.Column.PUID] = *((operator.Column.PN.Copy()).(*interpreter.TableNode))
+		}
+
+		return qu, nil
+	},
+}
+
+//findInFilter returns the in filter in the query having the value with the given uid.
+//If not found, will return nil
+func findInFilter(qu *interpreter.Query, uid string) *interpreter.OperatorNode {
+	for i := range qu.Filters {
+		if hasValue(qu.Filters[i], uid) {
+			return &qu.Filters[i]
+		}
+	}
+	for i := range qu.FilterGroups {
+		for j := range qu.FilterGroups[i].Filters {
+			if hasValue(qu.FilterGroups[i].Filters[j], uid) {
+				return &qu.FilterGroups[i].Filters[j]
+			}
+		}
+	}
+	return nil
+}
+
+//hasValue returns true if the filter is an in filter having the value with the given uid
+func hasValue(f interpreter.OperatorNode, uid string) bool {
+	if f.Operation != interpreter.InOperator {
+		return false
+	}
+	for _, v := range f.Values {
+		if v.UID == uid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package rules
+
+import (
+	"strings"
+
+	"github.com/cuttle-ai/octopus/interpreter"
+)
+
+/*
+ * This file contains the rule defnitions for identifying the filters with numbers like sales more than 1.5k, price between $10 and $20
+ */
+
+//NumberFilter will mark all the filter associated in the query with <field> <operator> <number>
+var NumberFilter = interpreter.Rule{
+	Name:        "Filter with number",
+	Description: "This rule will find the filters in the query. It will assign a filter if found in the template <field> <operator> <number> where field is a numeric or string column",
+	Template:    []interpreter.Type{interpreter.Column, interpreter.Operator, interpreter.Number},
+	Resolve: func(qu interpreter.Query, toks []interpreter.FastToken, index int) (interpreter.Query, error) {
+		/*
+		 * If the column, operator, number in the given index is not resolved we will add it to the query as filters and mark them as resolved
+		 */
+		if index+2 >= len(toks) || len(toks[index].Columns) == 0 || len(toks[index+1].Operators) == 0 || len(toks[index+2].Numbers) == 0 {
+			//we don't have enough the tokens for the given index
+			return qu, nil
+		}
+		if toks[index].Columns[0].IsResolved() || toks[index+1].Operators[0].IsResolved() || toks[index+2].Numbers[0].IsResolved() {
+			//the column or operator or number is already resolved
+			return qu, nil
+		}
+		if toks[index+1].Operators[0].Operation == interpreter.BetweenOperator || toks[index].Columns[0].DataType == interpreter.DataTypeDate {
+			//between requires a range and numbers can't be compared with the dates
+			return qu, nil
+		}
+		toks[index].Columns[0].SetResolved(true)
+		toks[index+1].Operators[0].SetResolved(true)
+		toks[index+2].Numbers[0].SetResolved(true)
+		toks[index+1].Operators[0].Column = &toks[index].Columns[0]
+		toks[index+1].Operators[0].Number = &toks[index+2].Numbers[0]
+		if len(qu.Filters) == 0 {
+			qu.Filters = []interpreter.OperatorNode{}
+		}
+		qu.Filters = append(qu.Filters, toks[index+1].Operators[0])
+		qu.Tables[toks[index].Columns[0].PUID] = *((toks[index].Columns[0].PN.Copy()).(*interpreter.TableNode))
+
+		return qu, nil
+	},
+}
+
+//NumberRangeFilter will mark all the range filters in the query with <field> <between operator> <number> <unknown> <number>
+var NumberRangeFilter = interpreter.Rule{
+	Name:        "Range filter with numbers",
+	Description: "This rule will find the range filters in the query. It will assign a filter if found in the template <field> <operator> <number> <unknown> <number> where the operator is between and unknown is and/to",
+	Template:    []interpreter.Type{interpreter.Column, interpreter.Operator, interpreter.Number, interpreter.Unknown, interpreter.Number},
+	Resolve: func(qu interpreter.Query, toks []interpreter.FastToken, index int) (interpreter.Query, error) {
+		/*
+		 * If the column, operator, numbers and unknown in the given index is not resolved and operator is between we will proceed
+		 * Then we will add the filter to the query and mark the nodes as resolved
+		 */
+		if index+4 >= len(toks) || len(toks[index].Columns) == 0 || len(toks[index+1].Operators) == 0 ||
+			len(toks[index+2].Numbers) == 0 || len(toks[index+3].Unknowns) == 0 || len(toks[index+4].Numbers) == 0 {
+			//we don't have enough the tokens for the given index
+			return qu, nil
+		}
+		if toks[index].Columns[0].IsResolved() || toks[index+1].Operators[0].IsResolved() || toks[index+2].Numbers[0].IsResolved() ||
+			toks[index+3].Unknowns[0].IsResolved() || toks[index+4].Numbers[0].IsResolved() {
+			//the column or operator or numbers or unknown is already resolved
+			return qu, nil
+		}
+		if toks[index+1].Operators[0].Operation != interpreter.BetweenOperator ||
+			!rangeConnectors[strings.ToLower(strings.TrimSpace(string(toks[index+3].Unknowns[0].Word)))] {
+			//the operator is not a range operator or the numbers are not joined as a range
+			return qu, nil
+		}
+		if toks[index].Columns[0].DataType == interpreter.DataTypeDate {
+			//numbers can't be compared with the dates
+			return qu, nil
+		}
+
+		toks[index].Columns[0].SetResolved(true)
+		toks[index+1].Operators[0].SetResolved(true)
+		toks[index+2].Numbers[0].SetResolved(true)
+		toks[index+3].Unknowns[0].SetResolved(true)
+		toks[index+4].Numbers[0].SetResolved(true)
+		toks[index+1].Operators[0].Column = &toks[index].Columns[0]
+		toks[index+1].Operators[0].Number = &toks[index+2].Numbers[0]
+		toks[index+1].Operators[0].ToNumber = &toks[index+4].Numbers[0]
+		if len(qu.Filters) == 0 {
+			qu.Filters = []interpreter.OperatorNode{}
+		}
+		qu.Filters = append(qu.Filters, toks[index+1].Operators[0])
+		qu.Tables[toks[index].Columns[0].PUID] = *((toks[index].Columns[0].PN.Copy()).(*interpreter.TableNode))
+
+		return qu, nil
+	},
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package rules
+
+import (
+	"strings"
+
+	"github.com/cuttle-ai/octopus/interpreter"
+)
+
+/*
+ * This file contains the rule defnition for joining the filters on the same column with or like price less than 10 or more than 20
+ */
+
+//OrFilter will join the filter with number found in the query with the filter in the template <number> <unknown> <operator> <number>
+//to an or filter group where the unknown is or
+var OrFilter = interpreter.Rule{
+	Name: "Or filter with numbers",
+	Description: "This rule will find the alternative conditions on a column in the query. It will join the filter with the first number to the filter in the template <number> <unknown> <operator> <number> " +
+		"as an or filter group on the same column where unknown is or. It has to be run after the filters with numbers are found",
+	Template: []interpreter.Type{interpreter.Number, interpreter.Unknown, interpreter.Operator, interpreter.Number},
+	Resolve: func(qu interpreter.Query, toks []interpreter.FastToken, index int) (interpreter.Query, error) {
+		/*
+		 * We will check whether the first number is part of a filter and the unknown is or
+		 * If the filter is already in an or group, the new filter is added to the group
+		 * Else we will replace the filter with an or group having the filter and the new filter
+		 */
+		if index+3 >= len(toks) || len(toks[index].Numbers) == 0 || len(toks[index+1].Unknowns) == 0 ||
+			len(toks[index+2].Operators) == 0 || len(toks[index+3].Numbers) == 0 {
+			//we don't have enough the tokens for the given index
+			return qu, nil
+		}
+		first, connector, operator, second := &toks[index].Numbers[0], &toks[index+1].Unknowns[0], &toks[index+2].Operators[0], &toks[index+3].Numbers[0]
+		if !first.IsResolved() || connector.IsResolved() || operator.IsResolved() || second.IsResolved() {
+			//the first number is not part of a filter or the rest are already resolved
+			return qu, nil
+		}
+		if strings.ToLower(strings.TrimSpace(string(connector.Word))) != "or" || operator.Operation == interpreter.BetweenOperator {
+			//the unknown is not or or the operator requires a range
+			return qu, nil
+		}
+
+		//if the number is part of an or group we will add the filter to it
+		for i := range qu.FilterGroups {
+			if qu.FilterGroups[i].Logic != interpreter.OrLogic {
+				continue
+			}
+			for _, f := range qu.FilterGroups[i].Filters {
+				if f.Number == nil || f.Number.UID != first.UID {
+					continue
+				}
+				connector.SetResolved(true)
+				operator.SetResolved(true)
+				second.SetResolved(true)
+				operator.Column = f.Column
+				operator.Number = second
+				qu.FilterGroups[i].Filters = append(qu.FilterGroups[i].Filters, *operator)
+				return qu, nil
+			}
+		}
+
+		//replacing the filter of the number with an or group
+		for i, f := range qu.Filters {
+			if f.Number == nil || f.Number.UID != first.UID {
+				continue
+			}
+			connector.SetResolved(true)
+			operator.SetResolved(true)
+			second.SetResolved(true)
+			operator.Column = f.Column
+			operator.Number = second
+			qu.Filters = append(qu.Filters[:i:i], qu.Filters[i+1:]...)
+			qu.FilterGroups = append(qu.FilterGroups, interpreter.FilterGroup{
+				Logic:   interpreter.OrLogic,
+				Filters: []interpreter.OperatorNode{f, *operator},
+			})
+			return qu, nil
+		}
+
+		return qu, nil
+	},
+}
@@ -0,0 +1,186 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package rules
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/cuttle-ai/octopus/interpreter"
+)
+
+/*
+ * This file contains the rule defnitions for identifying the range filters like between x and y
+ */
+
+//rangeSeparator is the separator between the lower and upper bound of a range
+var rangeSeparator = regexp.MustCompile(`(?i)\s+(and|to)\s+|\s+-\s+`)
+
+//rangeConnectors are the words with which the time bounds of a range can be joined
+var rangeConnectors = map[string]bool{
+	"and": true,
+	"to":  true,
+	"-":   true,
+}
+
+//rangeStarts are the words with which a range can be started without a range operator like from x to y
+var rangeStarts = map[string]bool{
+	"from": true,
+}
+
+//RangeFilter will mark all the range filters in the query with <field> <between operator> <unknown> where unknown is of the form x and y
+var RangeFilter = interpreter.Rule{
+	Name:        "Range filter with unknowns",
+	Description: "This rule will find the range filters in the query. It will assign a filter if found in the template <field> <operator> <unknown> where the operator is between and the unknown is of the form <x> and <y> or <x> to <y>",
+	Template:    []interpreter.Type{interpreter.Column, interpreter.Operator, interpreter.Unknown},
+	Resolve: func(qu interpreter.Query, toks []interpreter.FastToken, index int) (interpreter.Query, error) {
+		/*
+		 * If the column, operator, unknown in the given index is not resolved and operator is between we will proceed
+		 * We will split the unknown into the lower and upper bounds
+		 * Then we will add the filter to the query and mark the nodes as resolved
+		 */
+		if index+2 >= len(toks) || len(toks[index].Columns) == 0 || len(toks[index+1].Operators) == 0 || len(toks[index+2].Unknowns) == 0 {
+			//we don't have enough the tokens for the given index
+			return qu, nil
+		}
+		if toks[index].Columns[0].IsResolved() || toks[index+1].Operators[0].IsResolved() || toks[index+2].Unknowns[0].IsResolved() {
+			//the column or operator or unknown is already resolved
+			return qu, nil
+		}
+		if toks[index+1].Operators[0].Operation != interpreter.BetweenOperator || toks[index+2].Unknowns[0].Literal {
+			//the operator is not a range operator or the unknown is a quoted literal which can't be split
+			return qu, nil
+		}
+
+		//splitting the unknown into the bounds
+		unknown := toks[index+2].Unknowns[0]
+		bounds := rangeSeparator.Split(strings.TrimSpace(string(unknown.Word)), -1)
+		if len(bounds) != 2 || len(strings.TrimSpace(bounds[0])) == 0 || len(strings.TrimSpace(bounds[1])) == 0 {
+			//the unknown is not a range
+			return qu, nil
+		}
+		from := &interpreter.UnknownNode{UID: unknown.UID + "-from", Word: []rune(strings.TrimSpace(bounds[0])), PUID: unknown.PUID, PN: unknown.PN, Resolved: true}
+		to := &interpreter.UnknownNode{UID: unknown.UID + "-to", Word: []rune(strings.TrimSpace(bounds[1])), PUID: unknown.PUID, PN: unknown.PN, Resolved: true}
+
+		toks[index].Columns[0].SetResolved(true)
+		toks[index+1].Operators[0].SetResolved(true)
+		toks[index+2].Unknowns[0].SetResolved(true)
+		toks[index+1].Operators[0].Column = &toks[index].Columns[0]
+		toks[index+1].Operators[0].Unknown = from
+		toks[index+1].Operators[0].ToUnknown = to
+		if len(qu.Filters) == 0 {
+			qu.Filters = []interpreter.OperatorNode{}
+		}
+		qu.Filters = append(qu.Filters, toks[index+1].Operators[0])
+		qu.Tables[toks[index].Columns[0].PUID] = *((toks[index].Columns[0].PN.Copy()).(*interpreter.TableNode))
+
+		return qu, nil
+	},
+}
+
+//ColumnTimeRangeFilter will mark all the range filters in the query with <field> <between operator> <time> <unknown> <time> where field has data type date
+var ColumnTimeRangeFilter = interpreter.Rule{
+	Name:        "Range filter with column and time",
+	Description: "This rule will find the range filters in the query. It will assign a filter if found in the template <field> <operator> <time> <unknown> <time> where the operator is between, unknown is and/to and field has data type date",
+	Template:    []interpreter.Type{interpreter.Column, interpreter.Operator, interpreter.Time, interpreter.Unknown, interpreter.Time},
+	Resolve: func(qu interpreter.Query, toks []interpreter.FastToken, index int) (interpreter.Query, error) {
+		/*
+		 * If the column, operator, times and unknown in the given index is not resolved and operator is between we will proceed
+		 * Then we will add the filter to the query and mark the nodes as resolved
+		 */
+		if index+4 >= len(toks) || len(toks[index].Columns) == 0 || len(toks[index+1].Operators) == 0 ||
+			len(toks[index+2].Times) == 0 || len(toks[index+3].Unknowns) == 0 || len(toks[index+4].Times) == 0 {
+			//we don't have enough the tokens for the given index
+			return qu, nil
+		}
+		if toks[index].Columns[0].IsResolved() || toks[index+1].Operators[0].IsResolved() || toks[index+2].Times[0].IsResolved() ||
+			toks[index+3].Unknowns[0].IsResolved() || toks[index+4].Times[0].IsResolved() {
+			//the column or operator or times or unknown is already resolved
+			return qu, nil
+		}
+		if toks[index+1].Operators[0].Operation != interpreter.BetweenOperator ||
+			!rangeConnectors[strings.ToLower(strings.TrimSpace(string(toks[index+3].Unknowns[0].Word)))] {
+			//the operator is not a range operator or the times are not joined as a range
+			return qu, nil
+		}
+		//if the data type of the column is not date, we will skip
+		if toks[index].Columns[0].DataType != interpreter.DataTypeDate {
+			return qu, nil
+		}
+
+		toks[index].Columns[0].SetResolved(true)
+		toks[index+1].Operators[0].SetResolved(true)
+		toks[index+2].Times[0].SetResolved(true)
+		toks[index+3].Unknowns[0].SetResolved(true)
+		toks[index+4].Times[0].SetResolved(true)
+		resolveFiscal(&toks[index+2].Times[0], toks[index].Columns[0].PN)
+		resolveFiscal(&toks[index+4].Times[0], toks[index].Columns[0].PN)
+		toks[index+1].Operators[0].Column = &toks[index].Columns[0]
+		toks[index+1].Operators[0].Time = &toks[index+2].Times[0]
+		toks[index+1].Operators[0].ToTime = &toks[index+4].Times[0]
+		if len(qu.Filters) == 0 {
+			qu.Filters = []interpreter.OperatorNode{}
+		}
+		qu.Filters = append(qu.Filters, toks[index+1].Operators[0])
+		qu.Tables[toks[index].Columns[0].PUID] = *((toks[index].Columns[0].PN.Copy()).(*interpreter.TableNode))
+
+		return qu, nil
+	},
+}
+
+//FromToRangeFilter will mark all the range filters in the query with <field> <unknown> <number> <unknown> <number> where unknowns are from and to
+var FromToRangeFilter = interpreter.Rule{
+	Name:        "Range filter with from and to",
+	Description: "This rule will find the range filters in the query. It will assign a filter if found in the template <field> <unknown> <number> <unknown> <number> where the first unknown is from and the second is to/and",
+	Template:    []interpreter.Type{interpreter.Column, interpreter.Unknown, interpreter.Number, interpreter.Unknown, interpreter.Number},
+	Resolve: func(qu interpreter.Query, toks []interpreter.FastToken, index int) (interpreter.Query, error) {
+		/*
+		 * If the column, unknowns and numbers in the given index is not resolved and the unknowns are from and to we will proceed
+		 * Since there is no operator in the sentence, we will create a between operator for the range
+		 * Then we will add the filter to the query and mark the nodes as resolved
+		 */
+		if index+4 >= len(toks) || len(toks[index].Columns) == 0 || len(toks[index+1].Unknowns) == 0 ||
+			len(toks[index+2].Numbers) == 0 || len(toks[index+3].Unknowns) == 0 || len(toks[index+4].Numbers) == 0 {
+			//we don't have enough the tokens for the given index
+			return qu, nil
+		}
+		if toks[index].Columns[0].IsResolved() || toks[index+1].Unknowns[0].IsResolved() || toks[index+2].Numbers[0].IsResolved() ||
+			toks[index+3].Unknowns[0].IsResolved() || toks[index+4].Numbers[0].IsResolved() {
+			//the column or unknowns or numbers is already resolved
+			return qu, nil
+		}
+		if !rangeStarts[strings.ToLower(strings.TrimSpace(string(toks[index+1].Unknowns[0].Word)))] ||
+			!rangeConnectors[strings.ToLower(strings.TrimSpace(string(toks[index+3].Unknowns[0].Word)))] {
+			//the numbers are not joined as a range
+			return qu, nil
+		}
+		if toks[index].Columns[0].DataType == interpreter.DataTypeDate {
+			//numbers can't be compared with the dates
+			return qu, nil
+		}
+
+		toks[index].Columns[0].SetResolved(true)
+		toks[index+1].Unknowns[0].SetResolved(true)
+		toks[index+2].Numbers[0].SetResolved(true)
+		toks[index+3].Unknowns[0].SetResolved(true)
+		toks[index+4].Numbers[0].SetResolved(true)
+		operator := interpreter.OperatorNode{
+			UID:       "Operator-" + toks[index+1].Unknowns[0].UID,
+			Word:      []rune("between"),
+			Resolved:  true,
+			Column:    &toks[index].Columns[0],
+			Number:    &toks[index+2].Numbers[0],
+			ToNumber:  &toks[index+4].Numbers[0],
+			Operation: interpreter.BetweenOperator,
+		}
+		if len(qu.Filters) == 0 {
+			qu.Filters = []interpreter.OperatorNode{}
+		}
+		qu.Filters = append(qu.Filters, operator)
+		qu.Tables[toks[index].Columns[0].PUID] = *((toks[index].Columns[0].PN.Copy()).(*interpreter.TableNode))
+
+		return qu, nil
+	},
+}
@@ -0,0 +1,145 @@
+// Copyright 2019 Melvin Davis<hi@melvindavis.me>. All rights reserved.
+// Use of this source code is governed by a Melvin Davis<hi@melvindavis.me>
+// license that can be found in the LICENSE file.
+
+package rules
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cuttle-ai/octopus/interpreter"
+)
+
+/*
+ * This file contains the rule defnitions for sorting and limiting the results of the query based on superlative/ranking words
+ */
+
+//RankWithLimit will sort and limit the results of the query if found in the template <rank> <unknown> where unknown is a number
+var RankWithLimit = interpreter.Rule{
+	Name:        "Rank with limit",
+	Description: "This rule will sort and limit the results of the query if found in the template <rank> <unknown> like top 5. The results are sorted by the measure being selected",
+	Template:    []interpreter.Type{interpreter.Rank, interpreter.Unknown},
+	Resolve: func(qu interpreter.Query, toks []interpreter.FastToken, index int) (interpreter.Query, error) {
+		/*
+		 * If the rank and unknown in the given index is not resolved and unknown is a number we will proceed further
+		 * We will find the column to sort the results with
+		 * Then we will add the ordering and limit to the query and mark the nodes as resolved
+		 */
+		if index+1 >= len(toks) || len(toks[index].Ranks) == 0 || len(toks[index+1].Unknowns) == 0 {
+			//we don't have enough the tokens for the given index
+			return qu, nil
+		}
+		if toks[index].Ranks[0].IsResolved() || toks[index+1].Unknowns[0].IsResolved() {
+			//the rank or unknown is already resolved
+			return qu, nil
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(string(toks[index+1].Unknowns[0].Word)))
+		if err != nil || limit <= 0 {
+			//unknown is not a valid limit
+			return qu, nil
+		}
+
+		//finding the column
+		column, ok := rankColumn(qu)
+		if !ok {
+			return qu, nil
+		}
+
+		toks[index].Ranks[0].SetResolved(true)
+		toks[index+1].Unknowns[0].SetResolved(true)
+		qu.OrderBy = append(qu.OrderBy, interpreter.Ordering{Column: column, Descending: toks[index].Ranks[0].Descending})
+		qu.Limit = limit
+
+		return qu, nil
+	},
+}
+
+//RankWithNumber will sort and limit the results of the query if found in the template <rank> <number> where number is a positive integer
+var RankWithNumber = interpreter.Rule{
+	Name:        "Rank with number",
+	Description: "This rule will sort and limit the results of the query if found in the template <rank> <number> like top 5. The results are sorted by the measure being selected",
+	Template:    []interpreter.Type{interpreter.Rank, interpreter.Number},
+	Resolve: func(qu interpreter.Query, toks []interpreter.FastToken, index int) (interpreter.Query, error) {
+		/*
+		 * If the rank and number in the given index is not resolved and number is a valid limit we will proceed further
+		 * We will find the column to sort the results with
+		 * Then we will add the ordering and limit to the query and mark the nodes as resolved
+		 */
+		if index+1 >= len(toks) || len(toks[index].Ranks) == 0 || len(toks[index+1].Numbers) == 0 {
+			//we don't have enough the tokens for the given index
+			return qu, nil
+		}
+		if toks[index].Ranks[0].IsResolved() || toks[index+1].Numbers[0].IsResolved() {
+			//the rank or number is already resolved
+			return qu, nil
+		}
+		number := toks[index+1].Numbers[0]
+		if !number.IsInteger() || number.Value <= 0 || number.Percent || len(number.Currency) != 0 {
+			//number is not a valid limit
+			return qu, nil
+		}
+
+		//finding the column
+		column, ok := rankColumn(qu)
+		if !ok {
+			return qu, nil
+		}
+
+		toks[index].Ranks[0].SetResolved(true)
+		toks[index+1].Numbers[0].SetResolved(true)
+		qu.OrderBy = append(qu.OrderBy, interpreter.Ordering{Column: column, Descending: toks[index].Ranks[0].Descending})
+		qu.Limit = int(number.Value)
+
+		return qu, nil
+	},
+}
+
+//RankResults will sort and limit the results of the query if found in the template <rank>
+var RankResults = interpreter.Rule{
+	Name:        "Rank",
+	Description: "This rule will sort the results of the query if found in the template <rank> like highest, lowest. The results are limited by the default limit of the rank word",
+	Template:    []interpreter.Type{interpreter.Rank},
+	Resolve: func(qu interpreter.Query, toks []interpreter.FastToken, index int) (interpreter.Query, error) {
+		/*
+		 * If the rank in the given index is not resolved we will find the column to sort the results with
+		 * Then we will add the ordering and the default limit to the query and mark the node as resolved
+		 */
+		if index >= len(toks) || len(toks[index].Ranks) == 0 {
+			//we don't have enough the tokens for the given index
+			return qu, nil
+		}
+		if toks[index].Ranks[0].IsResolved() {
+			//the rank is already resolved
+			return qu, nil
+		}
+
+		//finding the column
+		column, ok := rankColumn(qu)
+		if !ok {
+			return qu, nil
+		}
+
+		toks[index].Ranks[0].SetResolved(true)
+		qu.OrderBy = append(qu.OrderBy, interpreter.Ordering{Column: column, Descending: toks[index].Ranks[0].Descending})
+		if toks[index].Ranks[0].Limit > 0 {
+			qu.Limit = toks[index].Ranks[0].Limit
+		}
+
+		return qu, nil
+	},
+}
+
+//rankColumn returns the column based on which the results has to be ranked.
+//Measures in the select fields are preferred over the other select fields
+func rankColumn(qu interpreter.Query) (interpreter.ColumnNode, bool) {
+	for _, v := range qu.Select {
+		if v.Measure {
+			return v, true
+		}
+	}
+	if len(qu.Select) > 0 {
+		return qu.Select[0], true
+	}
+	return interpreter.ColumnNode{}, false
+}
@@ -5,7 +5,11 @@
 //Package rules has the list of defaults rules for the octopus interpreter
 package rules
 
-import "github.com/cuttle-ai/octopus/interpreter"
+import (
+	"time"
+
+	"github.com/cuttle-ai/octopus/interpreter"
+)
 
 /*
  * This file contains the list of default rules to be loaded to be added
@@ -16,16 +20,39 @@ const DefaultRulesTag = "DEFAULT_RULES"
 
 //LoadDefaultRules will load the default rules to the interpreter rule engine
 func LoadDefaultRules() {
-	interpreter.AddRule(UnknownFilter, 0, 0, DefaultRulesTag)
-	interpreter.AddRule(ValueFilter, 0, 1, DefaultRulesTag)
-	interpreter.AddRule(DefaultOperatorValueFilter, 0, 2, DefaultRulesTag)
-	interpreter.AddRule(ColumnTimeFilter, 0, 3, DefaultRulesTag)
-	interpreter.AddRule(FilterValue, 0, 4, DefaultRulesTag)
-	interpreter.AddRule(GroupByColumn, 0, 5, DefaultRulesTag)
-	interpreter.AddRule(SelectColumn, 0, 6, DefaultRulesTag)
-	interpreter.AddRule(AtleastOneColumnFromGroupBy, 0, 7, DefaultRulesTag)
-	interpreter.AddRule(AtleastOneColumnFromFilter, 0, 8, DefaultRulesTag)
-	interpreter.AddRule(AtleastOneColumnFromValue, 0, 9, DefaultRulesTag)
-	interpreter.AddRule(TimeFilter, 0, 10, DefaultRulesTag)
-	interpreter.AddRule(AggregationFnWhenGroupBy, 0, 11, DefaultRulesTag)
+	interpreter.AddRule(InFilter, 0, 0, DefaultRulesTag)
+	interpreter.AddRule(RangeFilter, 0, 1, DefaultRulesTag)
+	interpreter.AddRule(NumberRangeFilter, 0, 2, DefaultRulesTag)
+	interpreter.AddRule(FromToRangeFilter, 0, 3, DefaultRulesTag)
+	interpreter.AddRule(ColumnTimeRangeFilter, 0, 4, DefaultRulesTag)
+	interpreter.AddRule(UnknownFilter, 0, 5, DefaultRulesTag)
+	interpreter.AddRule(NumberFilter, 0, 6, DefaultRulesTag)
+	interpreter.AddRule(ValueFilter, 0, 7, DefaultRulesTag)
+	interpreter.AddRule(DefaultOperatorValueFilter, 0, 8, DefaultRulesTag)
+	interpreter.AddRule(ColumnTimeFilter, 0, 9, DefaultRulesTag)
+	interpreter.AddRule(FilterValue, 0, 10, DefaultRulesTag)
+	interpreter.AddRule(GroupByColumn, 0, 11, DefaultRulesTag)
+	interpreter.AddRule(SelectColumn, 0, 12, DefaultRulesTag)
+	interpreter.AddRule(AtleastOneColumnFromGroupBy, 0, 13, DefaultRulesTag)
+	interpreter.AddRule(AtleastOneColumnFromFilter, 0, 14, DefaultRulesTag)
+	interpreter.AddRule(AtleastOneColumnFromValue, 0, 15, DefaultRulesTag)
+	interpreter.AddRule(AtleastOneColumnFromNumber, 0, 16, DefaultRulesTag)
+	interpreter.AddRule(OrFilter, 0, 17, DefaultRulesTag)
+	interpreter.AddRule(TimeFilter, 0, 18, DefaultRulesTag)
+	interpreter.AddRule(AggregationFnWhenGroupBy, 0, 19, DefaultRulesTag)
+	interpreter.AddRule(RankWithLimit, 0, 20, DefaultRulesTag)
+	interpreter.AddRule(RankWithNumber, 0, 21, DefaultRulesTag)
+	interpreter.AddRule(RankResults, 0, 22, DefaultRulesTag)
+	interpreter.AddRule(HavingFilter, 0, 23, DefaultRulesTag)
+}
+
+//resolveFiscal resolves the fiscal period in the time node to a date range in the fiscal calendar of the table.
+//If the table doesn't have a fiscal calendar, the one of its knowledge base is used.
+//If the table is not available or neither has a fiscal calendar, the fiscal year is considered to be the calendar year
+func resolveFiscal(t *interpreter.TimeNode, table *interpreter.TableNode) {
+	start := time.January
+	if table != nil {
+		start = table.FiscalYear()
+	}
+	t.Value = t.Value.ResolveFiscal(start)
 }
@@ -5,21 +5,26 @@
 package rules
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/cuttle-ai/octopus/datetime"
 	"github.com/cuttle-ai/octopus/interpreter"
+	"github.com/cuttle-ai/octopus/testutils"
 )
 
 /*
  * This file contains the test and test utilities for testing dictionary
  */
 
-var testColumn = &interpreter.ColumnNode{UID: "cars", Word: []rune("cars")}
+var testTable = &interpreter.TableNode{UID: "automobiles", Name: "automobiles"}
 
-var testColumn1 = &interpreter.ColumnNode{UID: "brands", Word: []rune("brand")}
+var testColumn = &interpreter.ColumnNode{UID: "cars", Word: []rune("cars"), PN: testTable, PUID: "automobiles"}
+
+var testColumn1 = &interpreter.ColumnNode{UID: "brands", Word: []rune("brand"), PN: testTable, PUID: "automobiles"}
 
 var testValue = &interpreter.ValueNode{UID: "swift", Word: []rune("Swift"), PN: testColumn, PUID: "cars"}
 
@@ -72,3 +77,391 @@ func TestRules(t *testing.T) {
 
 	fmt.Println(qu.Select)
 }
+
+func TestRankWithLimit(t *testing.T) {
+	sales := interpreter.ColumnNode{UID: "sales", Name: "sales", Measure: true, DataType: interpreter.DataTypeInt}
+	toks := []interpreter.FastToken{
+		{Pos: 0, Word: []rune("top"), Ranks: []interpreter.RankNode{{UID: "top", Word: []rune("top"), Descending: true}}},
+		{Pos: 1, Word: []rune(" 5 "), Unknowns: []interpreter.UnknownNode{{UID: "U1", Word: []rune(" 5 ")}}},
+	}
+	qu := interpreter.Query{Tables: map[string]interpreter.TableNode{}, Select: []interpreter.ColumnNode{sales}}
+	qu, err := RankWithLimit.Resolve(qu, toks, 0)
+	if err != nil {
+		t.Fatal("error while resolving the rule", err)
+	}
+	if qu.Limit != 5 {
+		t.Error("Expected the limit to be 5. Got", qu.Limit)
+	}
+	if len(qu.OrderBy) != 1 || qu.OrderBy[0].Column.Name != "sales" || !qu.OrderBy[0].Descending {
+		t.Error("Expected the results to be sorted in descending order of sales. Got", qu.OrderBy)
+	}
+	if !toks[0].Ranks[0].IsResolved() || !toks[1].Unknowns[0].IsResolved() {
+		t.Error("Expected the rank and unknown nodes to be resolved")
+	}
+}
+
+func TestHavingFilter(t *testing.T) {
+	LoadDefaultRules()
+	table := &interpreter.TableNode{UID: "automobile-sales", Name: "automobile sales"}
+	brand := &interpreter.ColumnNode{UID: "brand", PUID: table.UID, PN: table, Name: "brand", Dimension: true, DataType: interpreter.DataTypeString}
+	sales := &interpreter.ColumnNode{UID: "sales", PUID: table.UID, PN: table, Name: "sales", Measure: true, DataType: interpreter.DataTypeInt}
+	more := &interpreter.OperatorNode{UID: "more-than", Operation: interpreter.GreaterOperator}
+	err := interpreter.AddDICT("having-user", interpreter.DICT{Map: map[string]interpreter.Token{
+		"brands":    {Word: []rune("brands"), Nodes: []interpreter.Node{brand}},
+		"sales":     {Word: []rune("sales"), Nodes: []interpreter.Node{sales}},
+		"more than": {Word: []rune("more than"), Nodes: []interpreter.Node{more}},
+	}})
+	if err != nil {
+		t.Fatal("error while adding the dictionary", err)
+	}
+	toks, err := interpreter.TokenizeContext(context.Background(), "having-user", []rune("brands with sales more than 1000"))
+	if err != nil {
+		t.Fatal("error while tokenizing the sentence", err)
+	}
+	qu, err := interpreter.Interpret(toks)
+	if err != nil {
+		t.Fatal("error while interpreting the tokens", err)
+	}
+	s, err := qu.ToSQL()
+	if err != nil {
+		t.Fatal("error while converting the query to sql", err)
+	}
+	expected := `SELECT "brand" FROM "automobile sales" GROUP BY "brand" HAVING SUM("sales") >= $1`
+	if s.Query != expected {
+		t.Error("Expected query", "`"+expected+"`", "got", "`"+s.Query+"`")
+	}
+	if len(s.Args) != 1 || s.Args[0] != int64(1000) {
+		t.Error("Expected the having filter to be bound with 1000. Got", s.Args)
+	}
+
+	//the routing can be turned off like the other rules
+	position := -1
+	for i, r := range interpreter.GetRules()[0].Rules {
+		if r.Name == HavingFilter.Name {
+			position = i
+		}
+	}
+	if position < 0 {
+		t.Fatal("Expected the having filter to be in the default rules")
+	}
+	interpreter.SetRuleDisableState(0, position, true)
+	defer interpreter.SetRuleDisableState(0, position, false)
+	qu, err = interpreter.Interpret(toks)
+	if err != nil {
+		t.Fatal("error while interpreting the tokens", err)
+	}
+	if len(qu.Having) != 0 || len(qu.Filters) != 1 {
+		t.Error("Expected the filter on the measure to be kept in the filters when the rule is disabled. Got", qu.Having, qu.Filters)
+	}
+}
+
+func TestInFilter(t *testing.T) {
+	table := &interpreter.TableNode{UID: "automobile-sales", Name: "automobile sales"}
+	car := &interpreter.ColumnNode{UID: "car", PUID: "automobile-sales", PN: table, Name: "car", DataType: interpreter.DataTypeString}
+	toks := []interpreter.FastToken{
+		{Pos: 0, Word: []rune("car"), Columns: []interpreter.ColumnNode{*car}},
+		{Pos: 1, Word: []rune("not"), Operators: []interpreter.OperatorNode{{UID: "not", Operation: interpreter.NotEqOperator}}},
+		{Pos: 2, Word: []rune("Swift"), Values: []interpreter.ValueNode{{UID: "swift", Name: "Swift", PUID: "car", PN: car}}},
+		{Pos: 3, Word: []rune(", "), Unknowns: []interpreter.UnknownNode{{UID: "U3", Word: []rune(", ")}}},
+		{Pos: 4, Word: []rune("Alto"), Values: []interpreter.ValueNode{{UID: "alto", Name: "Alto", PUID: "car", PN: car}}},
+		{Pos: 5, Word: []rune(" or "), Unknowns: []interpreter.UnknownNode{{UID: "U5", Word: []rune(" or ")}}},
+		{Pos: 6, Word: []rune("Baleno"), Values: []interpreter.ValueNode{{UID: "baleno", Name: "Baleno", PUID: "car", PN: car}}},
+	}
+	qu := interpreter.Query{Tables: map[string]interpreter.TableNode{}}
+	var err error
+	for _, i := range []int{2, 4} {
+		qu, err = InFilter.Resolve(qu, toks, i)
+		if err != nil {
+			t.Fatal("error while resolving the rule", err)
+		}
+	}
+	if len(qu.Filters) != 0 || len(qu.FilterGroups) != 1 || qu.FilterGroups[0].Logic != interpreter.NotLogic {
+		t.Fatal("Expected a negated filter group. Got", qu.Filters, qu.FilterGroups)
+	}
+	f := qu.FilterGroups[0].Filters[0]
+	if f.Operation != interpreter.InOperator || len(f.Values) != 3 {
+		t.Error("Expected an in filter with 3 values. Got", f.Operation, f.Values)
+	}
+	for i, tok := range toks {
+		if len(tok.Columns) > 0 && !tok.Columns[0].IsResolved() ||
+			len(tok.Operators) > 0 && !tok.Operators[0].IsResolved() ||
+			len(tok.Values) > 0 && !tok.Values[0].IsResolved() ||
+			len(tok.Unknowns) > 0 && !tok.Unknowns[0].IsResolved() {
+			t.Error("Expected the token at", i, "to be resolved")
+		}
+	}
+}
+
+func TestInFilterAnd(t *testing.T) {
+	table := &interpreter.TableNode{UID: "automobile-sales", Name: "automobile sales"}
+	car := &interpreter.ColumnNode{UID: "car", PUID: "automobile-sales", PN: table, Name: "car", DataType: interpreter.DataTypeString}
+	toks := []interpreter.FastToken{
+		{Pos: 0, Word: []rune("Swift"), Values: []interpreter.ValueNode{{UID: "swift", Name: "Swift", PUID: "car", PN: car}}},
+		{Pos: 1, Word: []rune(" and "), Unknowns: []interpreter.UnknownNode{{UID: "U1", Word: []rune(" and ")}}},
+		{Pos: 2, Word: []rune("Alto"), Values: []interpreter.ValueNode{{UID: "alto", Name: "Alto", PUID: "car", PN: car}}},
+	}
+	qu, err := InFilter.Resolve(interpreter.Query{Tables: map[string]interpreter.TableNode{}}, toks, 0)
+	if err != nil {
+		t.Fatal("error while resolving the rule", err)
+	}
+	if len(qu.Filters) != 0 || len(qu.FilterGroups) != 0 {
+		t.Error("Expected the values joined by and not to be collapsed to an in filter. Got", qu.Filters, qu.FilterGroups)
+	}
+	if toks[0].Values[0].IsResolved() || toks[1].Unknowns[0].IsResolved() || toks[2].Values[0].IsResolved() {
+		t.Error("Expected the tokens to be left unresolved for the other rules")
+	}
+}
+
+func TestOrFilter(t *testing.T) {
+	LoadDefaultRules()
+	table := &interpreter.TableNode{UID: "automobile-sales", Name: "automobile sales"}
+	price := &interpreter.ColumnNode{UID: "price", PUID: table.UID, PN: table, Name: "price", DataType: interpreter.DataTypeFloat}
+	less := &interpreter.OperatorNode{UID: "less-than", Operation: interpreter.LessOperator}
+	more := &interpreter.OperatorNode{UID: "more-than", Operation: interpreter.GreaterOperator}
+	err := interpreter.AddDICT("or-user", interpreter.DICT{Map: map[string]interpreter.Token{
+		"price":     {Word: []rune("price"), Nodes: []interpreter.Node{price}},
+		"less than": {Word: []rune("less than"), Nodes: []interpreter.Node{less}},
+		"more than": {Word: []rune("more than"), Nodes: []interpreter.Node{more}},
+	}})
+	if err != nil {
+		t.Fatal("error while adding the dictionary", err)
+	}
+	toks, err := interpreter.TokenizeContext(context.Background(), "or-user", []rune("price less than 10 or more than 20"))
+	if err != nil {
+		t.Fatal("error while tokenizing the sentence", err)
+	}
+	qu, err := interpreter.Interpret(toks)
+	if err != nil {
+		t.Fatal("error while interpreting the tokens", err)
+	}
+	s, err := qu.ToSQL()
+	if err != nil {
+		t.Fatal("error while converting the query to sql", err)
+	}
+	expected := `SELECT "price" FROM "automobile sales" WHERE ("price" <= $1 OR "price" >= $2)`
+	if s.Query != expected {
+		t.Error("Expected query", "`"+expected+"`", "got", "`"+s.Query+"`")
+	}
+	if len(s.Args) != 2 || s.Args[0] != float64(10) || s.Args[1] != float64(20) {
+		t.Error("Expected the args to be 10 and 20. Got", s.Args)
+	}
+}
+
+func TestRangeFilter(t *testing.T) {
+	table := &interpreter.TableNode{UID: "automobile-sales", Name: "automobile sales"}
+	sales := interpreter.ColumnNode{UID: "sales", PUID: "automobile-sales", PN: table, Name: "sales", Measure: true, DataType: interpreter.DataTypeInt}
+	toks := []interpreter.FastToken{
+		{Pos: 0, Word: []rune("sales"), Columns: []interpreter.ColumnNode{sales}},
+		{Pos: 1, Word: []rune("between"), Operators: []interpreter.OperatorNode{{UID: "between", Operation: interpreter.BetweenOperator}}},
+		{Pos: 2, Word: []rune(" 100 and 500"), Unknowns: []interpreter.UnknownNode{{UID: "U2", Word: []rune(" 100 and 500")}}},
+	}
+	qu := interpreter.Query{Tables: map[string]interpreter.TableNode{}, Select: []interpreter.ColumnNode{sales}}
+	qu, err := RangeFilter.Resolve(qu, toks, 0)
+	if err != nil {
+		t.Fatal("error while resolving the rule", err)
+	}
+	if len(qu.Filters) != 1 || qu.Filters[0].Unknown == nil || qu.Filters[0].ToUnknown == nil {
+		t.Fatal("Expected a range filter with lower and upper bounds. Got", qu.Filters)
+	}
+	if string(qu.Filters[0].Unknown.Word) != "100" || string(qu.Filters[0].ToUnknown.Word) != "500" {
+		t.Error("Expected the range to be 100 and 500. Got", string(qu.Filters[0].Unknown.Word), string(qu.Filters[0].ToUnknown.Word))
+	}
+	s, err := qu.ToSQL()
+	if err != nil {
+		t.Fatal("error while converting the query to sql", err)
+	}
+	expected := `SELECT "sales" FROM "automobile sales" WHERE "sales" BETWEEN $1 AND $2`
+	if s.Query != expected {
+		t.Error("Expected query", "`"+expected+"`", "got", "`"+s.Query+"`")
+	}
+	if len(s.Args) != 2 || s.Args[0] != int64(100) || s.Args[1] != int64(500) {
+		t.Error("Expected the args to be typed integers 100 and 500. Got", s.Args)
+	}
+}
+
+type fiscalTimeFilterTest struct {
+	testutils.Test
+	Table *interpreter.TableNode
+}
+
+var fiscalKB = &interpreter.KnowledgeBaseNode{UID: "automobiles", Name: "automobiles", FiscalYearStart: time.April}
+
+var fiscalTimeFilterTestcases = []fiscalTimeFilterTest{
+	{
+		Test:  testutils.Test{Name: "Table", Description: "the fiscal calendar of the table is used"},
+		Table: &interpreter.TableNode{UID: "automobile-sales", Name: "automobile sales", FiscalYearStart: time.April},
+	},
+	{
+		Test:  testutils.Test{Name: "Knowledge base", Description: "the fiscal calendar of the knowledge base is used if the table doesn't have one"},
+		Table: &interpreter.TableNode{UID: "automobile-sales", Name: "automobile sales", PUID: fiscalKB.UID, PN: fiscalKB},
+	},
+	{
+		Test:  testutils.Test{Name: "Table over knowledge base", Description: "the fiscal calendar of the table overrides the one of the knowledge base"},
+		Table: &interpreter.TableNode{UID: "automobile-sales", Name: "automobile sales", FiscalYearStart: time.April, PUID: "automobiles", PN: &interpreter.KnowledgeBaseNode{UID: "automobiles", FiscalYearStart: time.July}},
+	},
+}
+
+func TestFiscalTimeFilter(t *testing.T) {
+	for _, v := range fiscalTimeFilterTestcases {
+		t.Run(v.Name, func(t *testing.T) {
+			year := interpreter.ColumnNode{UID: "financial-year", PUID: "automobile-sales", PN: v.Table, Name: "financial year", DataType: interpreter.DataTypeDate}
+			ref := time.Date(2019, time.May, 21, 0, 0, 0, 0, time.UTC)
+			fiscal := datetime.ParseFiscal([]rune("FY19 Q2"), ref).Res[0].Value
+			toks := []interpreter.FastToken{
+				{Pos: 0, Word: []rune("financial year"), Columns: []interpreter.ColumnNode{year}},
+				{Pos: 1, Word: []rune("in"), Operators: []interpreter.OperatorNode{{UID: "in", Operation: interpreter.EqOperator}}},
+				{Pos: 2, Word: []rune("FY19 Q2"), Times: []interpreter.TimeNode{{UID: "T0", Word: []rune("FY19 Q2"), Value: fiscal}}},
+			}
+			qu := interpreter.Query{Tables: map[string]interpreter.TableNode{}}
+			qu, err := ColumnTimeFilter.Resolve(qu, toks, 0)
+			if err != nil {
+				t.Fatal("error while resolving the rule", err)
+			}
+			if len(qu.Filters) != 1 || qu.Filters[0].Time == nil {
+				t.Fatal("Expected a time filter. Got", qu.Filters)
+			}
+			from, to := qu.Filters[0].Time.Value.Bounds()
+			if expected := time.Date(2018, time.July, 1, 0, 0, 0, 0, time.UTC); from == nil || !from.Equal(expected) {
+				t.Error("Expected the second fiscal quarter to start from", expected, "got", from)
+			}
+			if expected := time.Date(2018, time.October, 1, 0, 0, 0, 0, time.UTC); to == nil || !to.Equal(expected) {
+				t.Error("Expected the second fiscal quarter to end at", expected, "got", to)
+			}
+		})
+	}
+}
+
+func TestNumberFilter(t *testing.T) {
+	table := &interpreter.TableNode{UID: "automobile-sales", Name: "automobile sales"}
+	sales := interpreter.ColumnNode{UID: "sales", PUID: "automobile-sales", PN: table, Name: "sales", Measure: true, DataType: interpreter.DataTypeInt}
+	number, _ := interpreter.ParseNumber("1.5k")
+	toks := []interpreter.FastToken{
+		{Pos: 0, Word: []rune("sales"), Columns: []interpreter.ColumnNode{sales}},
+		{Pos: 1, Word: []rune("more than"), Operators: []interpreter.OperatorNode{{UID: "more-than", Operation: interpreter.GreaterOperator}}},
+		{Pos: 2, Word: number.Word, Numbers: []interpreter.NumberNode{*number}},
+	}
+	qu := interpreter.Query{Tables: map[string]interpreter.TableNode{}, Select: []interpreter.ColumnNode{sales}}
+	qu, err := NumberFilter.Resolve(qu, toks, 0)
+	if err != nil {
+		t.Fatal("error while resolving the rule", err)
+	}
+	if len(qu.Filters) != 1 || qu.Filters[0].Number == nil || !toks[2].Numbers[0].IsResolved() {
+		t.Fatal("Expected a filter with the number. Got", qu.Filters)
+	}
+	s, err := qu.ToSQL()
+	if err != nil {
+		t.Fatal("error while converting the query to sql", err)
+	}
+	if len(s.Args) != 1 || s.Args[0] != int64(1500) {
+		t.Error("Expected the arg to be the integer 1500. Got", s.Args)
+	}
+}
+
+func TestNumberRangeFilter(t *testing.T) {
+	table := &interpreter.TableNode{UID: "automobile-sales", Name: "automobile sales"}
+	price := interpreter.ColumnNode{UID: "price", PUID: "automobile-sales", PN: table, Name: "price", DataType: interpreter.DataTypeFloat}
+	from, _ := interpreter.ParseNumber("$10")
+	to, _ := interpreter.ParseNumber("$20.5")
+	toks := []interpreter.FastToken{
+		{Pos: 0, Word: []rune("price"), Columns: []interpreter.ColumnNode{price}},
+		{Pos: 1, Word: []rune("between"), Operators: []interpreter.OperatorNode{{UID: "between", Operation: interpreter.BetweenOperator}}},
+		{Pos: 2, Word: from.Word, Numbers: []interpreter.NumberNode{*from}},
+		{Pos: 3, Word: []rune(" and "), Unknowns: []interpreter.UnknownNode{{UID: "U3", Word: []rune(" and ")}}},
+		{Pos: 4, Word: to.Word, Numbers: []interpreter.NumberNode{*to}},
+	}
+	qu := interpreter.Query{Tables: map[string]interpreter.TableNode{}, Select: []interpreter.ColumnNode{price}}
+	qu, err := NumberRangeFilter.Resolve(qu, toks, 0)
+	if err != nil {
+		t.Fatal("error while resolving the rule", err)
+	}
+	if len(qu.Filters) != 1 || qu.Filters[0].Number == nil || qu.Filters[0].ToNumber == nil {
+		t.Fatal("Expected a range filter with the numbers. Got", qu.Filters)
+	}
+	s, err := qu.ToSQL()
+	if err != nil {
+		t.Fatal("error while converting the query to sql", err)
+	}
+	if len(s.Args) != 2 || s.Args[0] != float64(10) || s.Args[1] != 20.5 {
+		t.Error("Expected the args to be 10 and 20.5. Got", s.Args)
+	}
+}
+
+func TestFromToRangeFilter(t *testing.T) {
+	LoadDefaultRules()
+	table := &interpreter.TableNode{UID: "automobile-sales", Name: "automobile sales"}
+	price := &interpreter.ColumnNode{UID: "price", PUID: table.UID, PN: table, Name: "price", DataType: interpreter.DataTypeFloat}
+	err := interpreter.AddDICT("range-user", interpreter.DICT{Map: map[string]interpreter.Token{
+		"price": {Word: []rune("price"), Nodes: []interpreter.Node{price}},
+	}})
+	if err != nil {
+		t.Fatal("error while adding the dictionary", err)
+	}
+	toks, err := interpreter.TokenizeContext(context.Background(), "range-user", []rune("price from 10 to 20"))
+	if err != nil {
+		t.Fatal("error while tokenizing the sentence", err)
+	}
+	qu, err := interpreter.Interpret(toks)
+	if err != nil {
+		t.Fatal("error while interpreting the tokens", err)
+	}
+	s, err := qu.ToSQL()
+	if err != nil {
+		t.Fatal("error while converting the query to sql", err)
+	}
+	expected := `SELECT "price" FROM "automobile sales" WHERE "price" BETWEEN $1 AND $2`
+	if s.Query != expected {
+		t.Error("Expected query", "`"+expected+"`", "got", "`"+s.Query+"`")
+	}
+	if len(s.Args) != 2 || s.Args[0] != float64(10) || s.Args[1] != float64(20) {
+		t.Error("Expected the args to be 10 and 20. Got", s.Args)
+	}
+}
+
+func TestRankWithNumber(t *testing.T) {
+	sales := interpreter.ColumnNode{UID: "sales", Name: "sales", Measure: true, DataType: interpreter.DataTypeInt}
+	toks := []interpreter.FastToken{
+		{Pos: 0, Word: []rune("top"), Ranks: []interpreter.RankNode{{UID: "top", Word: []rune("top"), Descending: true}}},
+		{Pos: 1, Word: []rune("5"), Numbers: []interpreter.NumberNode{{UID: "N0", Word: []rune("5"), Value: 5}}},
+	}
+	qu := interpreter.Query{Tables: map[string]interpreter.TableNode{}, Select: []interpreter.ColumnNode{sales}}
+	qu, err := RankWithNumber.Resolve(qu, toks, 0)
+	if err != nil {
+		t.Fatal("error while resolving the rule", err)
+	}
+	if qu.Limit != 5 || len(qu.OrderBy) != 1 || !qu.OrderBy[0].Descending {
+		t.Error("Expected the top 5 results in the descending order of sales. Got", qu.Limit, qu.OrderBy)
+	}
+	if !toks[0].Ranks[0].IsResolved() || !toks[1].Numbers[0].IsResolved() {
+		t.Error("Expected the rank and number nodes to be resolved")
+	}
+}
+
+func TestUnknownFilterLiteral(t *testing.T) {
+	table := &interpreter.TableNode{UID: "automobile-sales", Name: "automobile sales"}
+	car := interpreter.ColumnNode{UID: "car", PUID: "automobile-sales", PN: table, Name: "car", DataType: interpreter.DataTypeString}
+	toks := []interpreter.FastToken{
+		{Pos: 0, Word: []rune("car"), Columns: []interpreter.ColumnNode{car}},
+		{Pos: 1, Word: []rune("is"), Operators: []interpreter.OperatorNode{{UID: "is", Operation: interpreter.EqOperator}}},
+		{Pos: 2, Word: []rune(`"Maruti Swift Dzire"`), Unknowns: []interpreter.UnknownNode{{UID: "L0", Word: []rune("Maruti Swift Dzire"), Literal: true}}},
+	}
+	qu := interpreter.Query{Tables: map[string]interpreter.TableNode{}, Select: []interpreter.ColumnNode{car}}
+	qu, err := UnknownFilter.Resolve(qu, toks, 0)
+	if err != nil {
+		t.Fatal("error while resolving the rule", err)
+	}
+	s, err := qu.ToSQL()
+	if err != nil {
+		t.Fatal("error while converting the query to sql", err)
+	}
+	if len(s.Args) != 1 || s.Args[0] != "Maruti Swift Dzire" {
+		t.Error("Expected the literal to be bound as it is. Got", s.Args)
+	}
+	toks = []interpreter.FastToken{
+		{Pos: 0, Word: []rune("car"), Columns: []interpreter.ColumnNode{car}},
+		{Pos: 1, Word: []rune("between"), Operators: []interpreter.OperatorNode{{UID: "between", Operation: interpreter.BetweenOperator}}},
+		{Pos: 2, Word: []rune(`"Alto and Swift"`), Unknowns: []interpreter.UnknownNode{{UID: "L0", Word: []rune("Alto and Swift"), Literal: true}}},
+	}
+	if qu, _ = RangeFilter.Resolve(interpreter.Query{Tables: map[string]interpreter.TableNode{}}, toks, 0); len(qu.Filters) != 0 {
+		t.Error("Expected the literal not to be split as a range. Got", qu.Filters)
+	}
+}
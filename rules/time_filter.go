@@ -36,16 +36,19 @@ var TimeFilter = interpreter.Rule{
 		//selecting the table
 		//then will iterate through the tables and check for the conditions
 		var selectedField *interpreter.ColumnNode
+		var selectedTable interpreter.TableNode
 		for _, t := range qu.Tables {
 			//first check for the default date field
 			//then check for the date type fields
 			if t.DefaultDateField != nil {
 				selectedField = t.DefaultDateField.Copy().(*interpreter.ColumnNode)
+				selectedTable = t
 				continue
 			}
 			for _, f := range t.Children {
 				if f.DataType == interpreter.DataTypeDate {
 					selectedField = f.Copy().(*interpreter.ColumnNode)
+					selectedTable = t
 				}
 			}
 		}
@@ -57,6 +60,7 @@ var TimeFilter = interpreter.Rule{
 
 		//marking the nodes as resolved
 		selectedField.SetResolved(true)
+		resolveFiscal(&toks[index].Times[0], &selectedTable)
 
 		operator := interpreter.OperatorNode{
 			UID:       "Operator-" + toks[index].Times[0].UID,
@@ -66,10 +70,11 @@ var TimeFilter = interpreter.Rule{
 			Time:      &toks[index].Times[0],
 			Operation: interpreter.EqOperator,
 		}
-		if toks[index].Times[0].Value.Type == "interval" && toks[index].Times[0].Value.From != nil {
+		//an interval open at one end is a since/till filter. Otherwise the filter spans the range of the time
+		if toks[index].Times[0].Value.Type == "interval" && toks[index].Times[0].Value.From != nil && toks[index].Times[0].Value.To == nil {
 			operator.Operation = interpreter.GreaterOperator
 			operator.Word = []rune("since")
-		} else if toks[index].Times[0].Value.Type == "interval" && toks[index].Times[0].Value.To != nil {
+		} else if toks[index].Times[0].Value.Type == "interval" && toks[index].Times[0].Value.To != nil && toks[index].Times[0].Value.From == nil {
 			operator.Operation = interpreter.LessOperator
 			operator.Word = []rune("till")
 		}